@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
+)
+
+// wmata-cache manages the on-disk response cache.
+//
+//	wmata-cache [-dir DIR] purge [-expired]
+func main() {
+	dir := flag.String("dir", "", "cache directory (defaults to the user cache directory)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-dir DIR] purge [-expired]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 || flag.Arg(0) != "purge" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	purgeFlags := flag.NewFlagSet("purge", flag.ExitOnError)
+	expired := purgeFlags.Bool("expired", false, "only remove expired entries")
+	purgeFlags.Parse(flag.Args()[1:])
+
+	if *dir == "" {
+		var err error
+		*dir, err = cache.DefaultDir()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	disk, err := cache.NewDisk(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	removed, err := disk.Purge(*expired)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("removed %d entries from %s\n", removed, *dir)
+}
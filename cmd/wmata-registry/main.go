@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/railstationinfo"
+)
+
+// wmata-registry generates the lines and stations registries from saved
+// GetLines and GetStations responses.
+//
+//	wmata-registry -kind lines -lines lines.json -o codes.go
+//	wmata-registry -kind stations -lines lines.json -stations stations.json -o codes.go
+func main() {
+	kind := flag.String("kind", "", "registry to generate: lines or stations")
+	linesFile := flag.String("lines", "", "GetLines response")
+	stationsFile := flag.String("stations", "", "GetStations response, for -kind stations")
+	out := flag.String("o", "", "output file")
+	flag.Parse()
+
+	if *linesFile == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var lines railstationinfo.LinesResponse
+	if err := readJSON(*linesFile, &lines); err != nil {
+		log.Fatal(err)
+	}
+
+	var src []byte
+	var err error
+	switch *kind {
+	case "lines":
+		src, err = generateLines(*linesFile, lines.Lines)
+	case "stations":
+		var stations railstationinfo.StationsResponse
+		if err := readJSON(*stationsFile, &stations); err != nil {
+			log.Fatal(err)
+		}
+		src, err = generateStations(*stationsFile, lines.Lines, stations.Stations)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readJSON(path string, v any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("json.Unmarshal (%s): %w", path, err)
+	}
+	return nil
+}
+
+func header(buf *bytes.Buffer, pkg, source string) {
+	fmt.Fprintf(buf, "// Code generated by wmata-registry from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+}
+
+func generateLines(source string, lines []railstationinfo.Line) ([]byte, error) {
+	sort.Slice(lines, func(i, j int) bool { return lines[i].LineCode < lines[j].LineCode })
+
+	var buf bytes.Buffer
+	header(&buf, "lines", source)
+	buf.WriteString("import \"github.com/thompsonja/wmata-go/pkg/ids\"\n\n")
+	buf.WriteString("const (\n")
+	for _, l := range lines {
+		if !l.LineCode.Valid() {
+			return nil, fmt.Errorf("invalid line code %q", l.LineCode)
+		}
+		fmt.Fprintf(&buf, "%s ids.LineCode = %q\n", identifier(l.DisplayName), l.LineCode)
+	}
+	buf.WriteString(")\n\n// All is every line.\nvar All = []ids.LineCode{\n")
+	for _, l := range lines {
+		fmt.Fprintf(&buf, "%s,\n", identifier(l.DisplayName))
+	}
+	buf.WriteString("}\n\nvar names = map[ids.LineCode]string{\n")
+	for _, l := range lines {
+		fmt.Fprintf(&buf, "%s: %q,\n", identifier(l.DisplayName), l.DisplayName)
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
+
+func generateStations(source string, lines []railstationinfo.Line, stations []railstationinfo.Station) ([]byte, error) {
+	sort.Slice(stations, func(i, j int) bool { return stations[i].Code < stations[j].Code })
+
+	lineNames := map[ids.LineCode]string{}
+	for _, l := range lines {
+		lineNames[l.LineCode] = identifier(l.DisplayName)
+	}
+	// Stations served by several platforms, such as Metro Center, have a
+	// code per platform. Their names are disambiguated by line.
+	count := map[string]int{}
+	for _, s := range stations {
+		count[identifier(s.Name)]++
+	}
+	names := make([]string, len(stations))
+	seen := map[string]bool{}
+	for i, s := range stations {
+		if !s.Code.Valid() {
+			return nil, fmt.Errorf("invalid station code %q", s.Code)
+		}
+		name := identifier(s.Name)
+		if count[name] > 1 {
+			line, ok := lineNames[s.LineCode1]
+			if !ok {
+				return nil, fmt.Errorf("station %s: unknown line %q", s.Code, s.LineCode1)
+			}
+			name += line
+		}
+		if seen[name] {
+			name += string(s.Code)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+
+	var buf bytes.Buffer
+	header(&buf, "stations", source)
+	buf.WriteString("import (\n\"github.com/thompsonja/wmata-go/pkg/ids\"\n\"github.com/thompsonja/wmata-go/pkg/lines\"\n)\n\n")
+	buf.WriteString("const (\n")
+	for i, s := range stations {
+		fmt.Fprintf(&buf, "%s ids.StationCode = %q\n", names[i], s.Code)
+	}
+	buf.WriteString(")\n\n// All is every station platform.\nvar All = []ids.StationCode{\n")
+	for i := range stations {
+		fmt.Fprintf(&buf, "%s,\n", names[i])
+	}
+	buf.WriteString("}\n\nvar stations = map[ids.StationCode]station{\n")
+	for i, s := range stations {
+		var codes []string
+		for _, code := range []*ids.LineCode{&s.LineCode1, s.LineCode2, s.LineCode3, s.LineCode4} {
+			if code == nil || *code == "" {
+				continue
+			}
+			line, ok := lineNames[*code]
+			if !ok {
+				return nil, fmt.Errorf("station %s: unknown line %q", s.Code, *code)
+			}
+			codes = append(codes, "lines."+line)
+		}
+		fmt.Fprintf(&buf, "%s: {name: %q, lines: []ids.LineCode{%s}", names[i], s.Name, strings.Join(codes, ", "))
+		if s.StationTogether1 != "" {
+			fmt.Fprintf(&buf, ", together: %q", s.StationTogether1)
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
+
+// identifier turns a name such as "Gallery Pl-Chinatown" into an exported Go
+// identifier, "GalleryPlChinatown".
+func identifier(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package wmata
+
+import (
+	"github.com/thompsonja/wmata-go/internal/helpers"
+)
+
+// APIError is returned, wrapped, by every API method when WMATA responds
+// with a non-200 status. Use errors.As to inspect it.
+type APIError = helpers.APIError
+
+// ErrorBody is the decoded WMATA error payload carried by an APIError.
+type ErrorBody = helpers.ErrorBody
+
+// Sentinel errors matching classes of APIError. Use errors.Is to test for them.
+var (
+	ErrUnauthorized = helpers.ErrUnauthorized
+	ErrRateLimited  = helpers.ErrRateLimited
+	ErrNotFound     = helpers.ErrNotFound
+	ErrServerError  = helpers.ErrServerError
+)
+
+// Errors returned by the client-side rate limiter.
+var (
+	ErrRateLimitExceeded   = helpers.ErrRateLimitExceeded
+	ErrDailyQuotaExhausted = helpers.ErrDailyQuotaExhausted
+)
+
+// ErrInvalidRequest is returned, before any network call, when the
+// parameters of a call are invalid.
+var ErrInvalidRequest = helpers.ErrInvalidRequest
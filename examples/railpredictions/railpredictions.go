@@ -6,7 +6,8 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/thompsonja/wmata-go/pkg/railpredictions"
+	"github.com/thompsonja/wmata-go"
+	"github.com/thompsonja/wmata-go/pkg/ids"
 )
 
 // This is an example of how to use one of the APIs, in this case the railpredictions API.
@@ -14,9 +15,9 @@ func main() {
 	apiKey := flag.String("api_key", "", "WMATA API key")
 	flag.Parse()
 
-	client := railpredictions.New(*apiKey)
+	client := wmata.New(*apiKey)
 
-	predictions, err := client.GetRailPredictions(context.Background(), "all")
+	predictions, err := client.RailPredictions.GetRailPredictions(context.Background(), ids.AllStations)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingServer answers every request once release is closed, and reports
+// the requests it sees canceled on canceled.
+type blockingServer struct {
+	*httptest.Server
+	requests atomic.Int32
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func newBlockingServer(t *testing.T) *blockingServer {
+	t.Helper()
+	s := &blockingServer{release: make(chan struct{}), canceled: make(chan struct{}, 10)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		select {
+		case <-s.release:
+			w.Write([]byte(`{"Lines":[]}`))
+		case <-r.Context().Done():
+			s.canceled <- struct{}{}
+		}
+	}))
+	t.Cleanup(s.Close)
+	t.Cleanup(func() {
+		select {
+		case <-s.release:
+		default:
+			close(s.release)
+		}
+	})
+	return s
+}
+
+func (s *blockingServer) requester() *HttpRequester {
+	return New("key", WithBaseURL(s.URL+"/"), WithRateLimit(RateLimit{}), WithRetryPolicy(NoRetry))
+}
+
+// waitForWaiters waits until n callers share the in-flight fetch.
+func waitForWaiters(t *testing.T, r *HttpRequester, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		r.flights.mu.Lock()
+		waiters := 0
+		for _, f := range r.flights.flights {
+			waiters += f.waiters
+		}
+		r.flights.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d waiters, want %d", waiters, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// callConcurrently starts a call per context and returns their errors once
+// they all return.
+func callConcurrently(r *HttpRequester, ctxs []context.Context) func() []error {
+	errs := make([]error, len(ctxs))
+	var wg sync.WaitGroup
+	for i, ctx := range ctxs {
+		wg.Add(1)
+		go func(i int, ctx context.Context) {
+			defer wg.Done()
+			_, errs[i] = Send(ctx, r, Lines, nil)
+		}(i, ctx)
+	}
+	return func() []error {
+		wg.Wait()
+		return errs
+	}
+}
+
+func TestCoalescing(t *testing.T) {
+	s := newBlockingServer(t)
+	r := s.requester()
+	ctxs := make([]context.Context, 10)
+	for i := range ctxs {
+		ctxs[i] = context.Background()
+	}
+	wait := callConcurrently(r, ctxs)
+	waitForWaiters(t, r, len(ctxs))
+	close(s.release)
+	for i, err := range wait() {
+		if err != nil {
+			t.Errorf("call %d: %v", i, err)
+		}
+	}
+	if n := s.requests.Load(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestCoalescingOneCallerCancels(t *testing.T) {
+	s := newBlockingServer(t)
+	r := s.requester()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctxs := []context.Context{ctx, context.Background(), context.Background()}
+	wait := callConcurrently(r, ctxs)
+	waitForWaiters(t, r, len(ctxs))
+
+	cancel()
+	waitForWaiters(t, r, len(ctxs)-1)
+	close(s.release)
+	errs := wait()
+	if !errors.Is(errs[0], context.Canceled) {
+		t.Errorf("canceled call: err = %v, want context.Canceled", errs[0])
+	}
+	for i, err := range errs[1:] {
+		if err != nil {
+			t.Errorf("call %d: %v", i+1, err)
+		}
+	}
+	if n := s.requests.Load(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestCoalescingAllCallersCancel(t *testing.T) {
+	s := newBlockingServer(t)
+	r := s.requester()
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := callConcurrently(r, []context.Context{ctx, ctx, ctx})
+	waitForWaiters(t, r, 3)
+
+	cancel()
+	for i, err := range wait() {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("call %d: err = %v, want context.Canceled", i, err)
+		}
+	}
+	select {
+	case <-s.canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the shared request was not canceled")
+	}
+
+	// The canceled fetch is not reused by later calls.
+	close(s.release)
+	if _, err := Send(context.Background(), r, Lines, nil); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if n := s.requests.Load(); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
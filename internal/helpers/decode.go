@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeObject decodes a JSON object into the struct v points to. Array
+// fields are decoded one element at a time, so that the decoder only ever
+// buffers a single element rather than the whole response. Values that are
+// not plain structs are decoded in one go.
+func decodeObject(dec *json.Decoder, v any) error {
+	rv := reflect.ValueOf(v).Elem()
+	if !plainStruct(rv.Type()) {
+		if err := dec.Decode(v); err != nil {
+			return fmt.Errorf("dec.Decode: %w", err)
+		}
+		return nil
+	}
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("dec.Token: %w", err)
+		}
+		name, _ := tok.(string)
+		field, ok := fieldByJSONName(rv, name)
+		if !ok {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return fmt.Errorf("dec.Decode: %w", err)
+			}
+			continue
+		}
+		if field.Kind() == reflect.Slice && !customDecoding(field.Type()) {
+			err = decodeSlice(dec, name, field)
+		} else if err = dec.Decode(field.Addr().Interface()); err != nil {
+			err = fmt.Errorf("dec.Decode: %w", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func decodeSlice(dec *json.Decoder, name string, slice reflect.Value) error {
+	// WMATA sends null rather than an empty array at times.
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("dec.Token: %w", err)
+	}
+	if tok == nil {
+		slice.SetZero()
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%s: expected array, got %v", name, tok)
+	}
+	if slice.IsNil() {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	}
+	slice.SetLen(0)
+	for n := 0; dec.More(); n++ {
+		slice.Grow(1)
+		slice.SetLen(n + 1)
+		elem := slice.Index(n)
+		elem.SetZero()
+		if err := dec.Decode(elem.Addr().Interface()); err != nil {
+			return fmt.Errorf("dec.Decode: %w", err)
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// fieldByJSONName finds the exported field encoding/json would decode the
+// key into: an exact match of its name or tag first, then a case-insensitive
+// one.
+func fieldByJSONName(rv reflect.Value, key string) (reflect.Value, bool) {
+	fold := -1
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		if name == key {
+			return rv.Field(i), true
+		}
+		if fold < 0 && strings.EqualFold(name, key) {
+			fold = i
+		}
+	}
+	if fold < 0 {
+		return reflect.Value{}, false
+	}
+	return rv.Field(fold), true
+}
+
+// plainStruct reports whether t is a struct without embedded fields or
+// custom decoding, whose keys fieldByJSONName can resolve.
+func plainStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || customDecoding(t) {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			return false
+		}
+	}
+	return true
+}
+
+func customDecoding(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(unmarshalerType)
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type decodeItem struct {
+	ID   int
+	Name string `json:"name"`
+}
+
+type decodeResponse struct {
+	Items   []decodeItem `json:"Items"`
+	Other   []string
+	Total   int
+	Ignored string `json:"-"`
+	Nested  struct{ A []int }
+}
+
+func TestDecodeObject(t *testing.T) {
+	for _, input := range []string{
+		`{"Items":[{"ID":1,"name":"a"},{"ID":2,"name":"b"}],"Total":2}`,
+		`{"items":[{"id":1}],"other":["x","y"],"Nested":{"A":[1,2]}}`,
+		`{"Items":null,"Other":[],"Unknown":{"Items":[1]},"Ignored":"x","-":"y"}`,
+		`{}`,
+	} {
+		var want, got decodeResponse
+		if err := json.Unmarshal([]byte(input), &want); err != nil {
+			t.Fatalf("%s: json.Unmarshal: %v", input, err)
+		}
+		if err := decodeObject(json.NewDecoder(strings.NewReader(input)), &got); err != nil {
+			t.Fatalf("%s: decodeObject: %v", input, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", input, got, want)
+		}
+	}
+}
+
+func TestDecodeObjectErrors(t *testing.T) {
+	for _, input := range []string{
+		`[]`,
+		`{"Items":{}}`,
+		`{"Items":[{"ID":"x"}]}`,
+		`{"Items":[{"ID":1}`,
+	} {
+		var got decodeResponse
+		if err := decodeObject(json.NewDecoder(strings.NewReader(input)), &got); err == nil {
+			t.Errorf("%s: decodeObject succeeded, want error", input)
+		}
+	}
+}
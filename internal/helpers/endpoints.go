@@ -0,0 +1,279 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	staticTTL     = 24 * time.Hour
+	scheduleTTL   = time.Hour
+	incidentTTL   = time.Minute
+	predictionTTL = 10 * time.Second
+	positionTTL   = 5 * time.Second
+)
+
+// Endpoint describes a WMATA API endpoint.
+type Endpoint struct {
+	// Path is relative to the base URL. Path parameters are written as
+	// {Name} and filled from the call parameters.
+	Path string
+	// Params are the query parameters documented for the endpoint.
+	Params []string
+	// Fixed are query parameters sent with every call.
+	Fixed map[string]string
+	// TTL is the default cache TTL. Zero disables caching.
+	TTL time.Duration
+	// Large endpoints have their responses decoded as they are read rather
+	// than buffered first, one array element at a time.
+	Large bool
+}
+
+var endpoints []*Endpoint
+
+func register(e Endpoint) *Endpoint {
+	endpoints = append(endpoints, &e)
+	return &e
+}
+
+// The WMATA endpoints. Adding an endpoint takes one line here and one method
+// calling Get in the package of its service.
+var (
+	BusPositions      = register(Endpoint{Path: "Bus.svc/json/jBusPositions", Params: []string{"RouteID", "Lat", "Lon", "Radius"}, TTL: predictionTTL})
+	RouteDetails      = register(Endpoint{Path: "Bus.svc/json/jRouteDetails", Params: []string{"RouteID", "Date"}, TTL: staticTTL})
+	Routes            = register(Endpoint{Path: "Bus.svc/json/jRoutes", TTL: staticTTL})
+	RouteSchedule     = register(Endpoint{Path: "Bus.svc/json/jRouteSchedule", Params: []string{"RouteID", "Date", "IncludingVariations"}, TTL: scheduleTTL, Large: true})
+	StopSchedule      = register(Endpoint{Path: "Bus.svc/json/jStopSchedule", Params: []string{"StopID", "Date", "IncludingVariations"}, TTL: scheduleTTL})
+	Stops             = register(Endpoint{Path: "Bus.svc/json/jStops", Params: []string{"Lat", "Lon", "Radius"}, TTL: staticTTL})
+	BusPredictions    = register(Endpoint{Path: "NextBusService.svc/json/jPredictions", Params: []string{"StopID"}, TTL: predictionTTL})
+	BusIncidents      = register(Endpoint{Path: "Incidents.svc/json/BusIncidents", Params: []string{"Route"}, TTL: incidentTTL})
+	ElevatorIncidents = register(Endpoint{Path: "Incidents.svc/json/ElevatorIncidents", Params: []string{"StationCode"}, TTL: incidentTTL})
+	RailIncidents     = register(Endpoint{Path: "Incidents.svc/json/Incidents", TTL: incidentTTL})
+	Validate          = register(Endpoint{Path: "Misc/Validate"})
+	RailPredictions   = register(Endpoint{Path: "StationPrediction.svc/json/GetPrediction/{StationCode}", TTL: predictionTTL})
+	Lines             = register(Endpoint{Path: "Rail.svc/json/jLines", TTL: staticTTL})
+	StationParking    = register(Endpoint{Path: "Rail.svc/json/jStationParking", Params: []string{"StationCode"}, TTL: staticTTL})
+	Path              = register(Endpoint{Path: "Rail.svc/json/jPath", Params: []string{"FromStationCode", "ToStationCode"}, TTL: staticTTL})
+	StationEntrances  = register(Endpoint{Path: "Rail.svc/json/jStationEntrances", Params: []string{"Lat", "Lon", "Radius"}, TTL: staticTTL})
+	StationInfo       = register(Endpoint{Path: "Rail.svc/json/jStationInfo", Params: []string{"StationCode"}, TTL: staticTTL})
+	Stations          = register(Endpoint{Path: "Rail.svc/json/jStations", Params: []string{"LineCode"}, TTL: staticTTL})
+	StationTimes      = register(Endpoint{Path: "Rail.svc/json/jStationTimes", Params: []string{"StationCode"}, TTL: staticTTL})
+	StationToStation  = register(Endpoint{Path: "Rail.svc/json/jSrcStationToDstStationInfo", Params: []string{"FromStationCode", "ToStationCode"}, TTL: staticTTL})
+	TrainPositions    = register(Endpoint{Path: "TrainPositions/TrainPositions", Fixed: map[string]string{"contentType": "json"}, TTL: positionTTL})
+	StandardRoutes    = register(Endpoint{Path: "TrainPositions/StandardRoutes", Fixed: map[string]string{"contentType": "json"}, TTL: staticTTL, Large: true})
+	TrackCircuits     = register(Endpoint{Path: "TrainPositions/TrackCircuits", Fixed: map[string]string{"contentType": "json"}, TTL: staticTTL, Large: true})
+)
+
+// Name returns the path of the endpoint without its path parameters, e.g.
+// "StationPrediction.svc/json/GetPrediction".
+func (e *Endpoint) Name() string {
+	if i := strings.Index(e.Path, "/{"); i >= 0 {
+		return e.Path[:i]
+	}
+	return e.Path
+}
+
+// match reports whether a request path was built from the endpoint, and
+// returns its path parameters.
+func (e *Endpoint) match(path string) (map[string]string, bool) {
+	pattern := strings.Split(e.Path, "/")
+	segments := strings.Split(path, "/")
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, p := range pattern {
+		if name, ok := strings.CutPrefix(p, "{"); ok {
+			if params == nil {
+				params = map[string]string{}
+			}
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				value = segments[i]
+			}
+			params[strings.TrimSuffix(name, "}")] = value
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// declares reports whether name is a documented query parameter of the
+// endpoint.
+func (e *Endpoint) declares(name string) bool {
+	for _, p := range e.Params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupEndpoint returns the endpoint a request path was built from, along
+// with its path parameters.
+func lookupEndpoint(path string) (*Endpoint, map[string]string) {
+	for _, e := range endpoints {
+		if params, ok := e.match(path); ok {
+			return e, params
+		}
+	}
+	return nil, nil
+}
+
+// url fills the path parameters of the endpoint and returns the request URL.
+func (e *Endpoint) url(r *HttpRequester, params map[string]string, opts []Option) (string, error) {
+	path := e.Path
+	query := make(map[string]string, len(params)+len(e.Fixed))
+	for k, v := range e.Fixed {
+		query[k] = v
+	}
+	for k, v := range params {
+		placeholder := "{" + k + "}"
+		if strings.Contains(path, placeholder) {
+			// Commas separate multiple station codes and must stay literal.
+			path = strings.ReplaceAll(path, placeholder, strings.ReplaceAll(url.PathEscape(v), "%2C", ","))
+		} else if e.declares(k) {
+			query[k] = v
+		} else {
+			// Undocumented parameters are ignored by WMATA, so sending one
+			// is a bug rather than something to pass through.
+			return "", fmt.Errorf("undeclared parameter %s for %s", k, e.Name())
+		}
+	}
+	if strings.Contains(path, "{") {
+		return "", fmt.Errorf("missing path parameter in %s", path)
+	}
+	return r.GenerateUrl(path, query, opts...)
+}
+
+// Send calls an endpoint and returns the raw response.
+func Send(ctx context.Context, r *HttpRequester, e *Endpoint, params map[string]string, opts ...Option) (*Result, error) {
+	url, err := e.url(r, params, opts)
+	if err != nil {
+		return nil, fmt.Errorf("e.url: %w", err)
+	}
+	res, err := r.Do(ctx, url, nil, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Do: %w", err)
+	}
+	return res, nil
+}
+
+// GetResult calls an endpoint and decodes its JSON response into a T,
+// returning the raw response along with it.
+func GetResult[T any](ctx context.Context, r *HttpRequester, e *Endpoint, params map[string]string, opts ...Option) (*T, *Result, error) {
+	var value T
+	if e.Large {
+		res, err := stream(ctx, r, e, params, func(body io.Reader) error {
+			return decodeObject(json.NewDecoder(body), &value)
+		}, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &value, res, nil
+	}
+
+	res, err := Send(ctx, r, e, params, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(res.Body, &value); err != nil {
+		return nil, nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return &value, res, nil
+}
+
+// Get calls an endpoint and decodes its JSON response into a T.
+func Get[T any](ctx context.Context, r *HttpRequester, e *Endpoint, params map[string]string, opts ...Option) (*T, error) {
+	value, _, err := GetResult[T](ctx, r, e, params, opts...)
+	return value, err
+}
+
+// ForEach calls an endpoint whose response is a JSON object and passes every
+// element of its array fields to fn as it is decoded, without materializing
+// the arrays. Other fields are skipped. Returning an error from fn stops the
+// iteration.
+func ForEach[T any](ctx context.Context, r *HttpRequester, e *Endpoint, params map[string]string, fn func(field string, value T) error, fields []string, opts ...Option) error {
+	wanted := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		wanted[f] = true
+	}
+	_, err := stream(ctx, r, e, params, func(body io.Reader) error {
+		dec := json.NewDecoder(body)
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("dec.Token: %w", err)
+			}
+			field, _ := tok.(string)
+			if !wanted[field] {
+				var skipped json.RawMessage
+				if err := dec.Decode(&skipped); err != nil {
+					return fmt.Errorf("dec.Decode: %w", err)
+				}
+				continue
+			}
+			if err := forEachElement(dec, field, fn); err != nil {
+				return err
+			}
+		}
+		return expectDelim(dec, '}')
+	}, opts)
+	return err
+}
+
+func forEachElement[T any](dec *json.Decoder, field string, fn func(string, T) error) error {
+	// WMATA sends null rather than an empty array at times.
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("dec.Token: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%s: expected array, got %v", field, tok)
+	}
+	for dec.More() {
+		var value T
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("dec.Decode: %w", err)
+		}
+		if err := fn(field, value); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("dec.Token: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+func stream(ctx context.Context, r *HttpRequester, e *Endpoint, params map[string]string, consume func(io.Reader) error, opts []Option) (*Result, error) {
+	url, err := e.url(r, params, opts)
+	if err != nil {
+		return nil, fmt.Errorf("e.url: %w", err)
+	}
+	res, err := r.Stream(ctx, url, consume, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("r.Stream: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrUnauthorized = errors.New("wmata: unauthorized")
+	ErrRateLimited  = errors.New("wmata: rate limited")
+	ErrNotFound     = errors.New("wmata: not found")
+	ErrServerError  = errors.New("wmata: server error")
+)
+
+// ErrorBody is the error payload returned by the WMATA API.
+type ErrorBody struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message"`
+}
+
+// APIError is returned when the WMATA API responds with a non-200 status.
+type APIError struct {
+	StatusCode int
+	Header     http.Header
+	Endpoint   string
+	Body       *ErrorBody
+	RawBody    []byte
+}
+
+// attemptsError records how many attempts a failed request was sent in.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string { return e.err.Error() }
+
+func (e *attemptsError) Unwrap() error { return e.err }
+
+func newAPIError(resp *http.Response, endpoint string, rawBody []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Endpoint:   endpoint,
+		RawBody:    rawBody,
+	}
+	var body ErrorBody
+	if err := json.Unmarshal(rawBody, &body); err == nil && (body.StatusCode != 0 || body.Message != "") {
+		e.Body = &body
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	if e.Body != nil && e.Body.Message != "" {
+		return fmt.Sprintf("wmata: %s: http status %d: %s", e.Endpoint, e.StatusCode, e.Body.Message)
+	}
+	return fmt.Sprintf("wmata: %s: http status %d", e.Endpoint, e.StatusCode)
+}
+
+// Is reports whether the error belongs to the class of one of the sentinel
+// errors, so that errors.Is(err, ErrRateLimited) works on wrapped APIErrors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
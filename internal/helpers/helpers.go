@@ -8,76 +8,368 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
 )
 
 const (
-	baseURL = "https://api.wmata.com/"
+	DefaultBaseURL = "https://api.wmata.com/"
 )
 
 type HttpRequester struct {
-	apiKey string
+	apiKey   string
+	settings Settings
+	client   *http.Client
+	limiter  *rateLimiter
+
+	// refreshing holds the URLs being revalidated in the background.
+	refreshing sync.Map
+	// flights coalesces concurrent requests for the same URL.
+	flights flightGroup
 }
 
-func New(apiKey string) *HttpRequester {
-	return &HttpRequester{
-		apiKey: apiKey,
+func New(apiKey string, opts ...Option) *HttpRequester {
+	s := defaultSettings()
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	r := &HttpRequester{
+		apiKey:   apiKey,
+		settings: s,
+		limiter:  newRateLimiter(s.RateLimit),
 	}
+	r.client = r.newClient(s)
+	return r
 }
 
-func (r *HttpRequester) SendHttpRequest(ctx context.Context, url string, data any) ([]byte, error) {
-	var buf io.Reader = nil
-	if data != nil {
-		var ok bool
-		buf, ok = data.(io.Reader)
-		if !ok {
-			var b []byte
-			b, err := json.Marshal(data)
-			if err != nil {
-				return nil, fmt.Errorf("json.Marshal: %v", err)
+// newClient returns a copy of the configured client whose transport runs the
+// middleware chain.
+func (r *HttpRequester) newClient(s Settings) *http.Client {
+	client := http.Client{Timeout: DefaultTimeout}
+	if s.HTTPClient != nil {
+		client = *s.HTTPClient
+	}
+	if s.Timeout > 0 {
+		client.Timeout = s.Timeout
+	}
+	base := client.Transport
+	if s.Transport != nil {
+		base = s.Transport
+	}
+	if base == nil {
+		base = DefaultTransport
+	}
+	client.Transport = r.chain(s, base)
+	return &client
+}
+
+// callSettings returns the requester settings with per-call options applied.
+func (r *HttpRequester) callSettings(opts []Option) (Settings, *http.Client) {
+	if len(opts) == 0 {
+		return r.settings, r.client
+	}
+	s := r.settings
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s, r.newClient(s)
+}
+
+func (r *HttpRequester) SendHttpRequest(ctx context.Context, url string, data any, opts ...Option) ([]byte, error) {
+	res, err := r.Do(ctx, url, data, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Result is a response body along with how it was obtained.
+type Result struct {
+	Body       []byte
+	Header     http.Header
+	StatusCode int
+	URL        string
+	FetchedAt  time.Time
+	Latency    time.Duration
+	CacheHit   bool
+	// Size is the size of the body in bytes. It is also set when the body
+	// was streamed rather than buffered.
+	Size int64
+	// Attempts is the number of requests sent to WMATA, including retries.
+	Attempts int
+	// Stale is set when an expired cache entry is served under
+	// WithStaleWhileRevalidate while it is refreshed in the background. It
+	// keeps being served until the refresh succeeds or it gets older than
+	// MaxStale, so that a failing WMATA does not fail the call. Age is then
+	// the time since the entry was fetched.
+	Stale bool
+	Age   time.Duration
+}
+
+// call holds the state of a single SendHttpRequest or Do call.
+type call struct {
+	s        Settings
+	client   *http.Client
+	method   string
+	url      string
+	endpoint string
+	body     []byte
+	ttl      time.Duration
+	// consume, if set, reads the response body as it is received instead of
+	// it being buffered.
+	consume func(io.Reader) error
+}
+
+func (c *call) cacheable() bool {
+	return c.s.Cache != nil && c.body == nil && c.ttl > 0
+}
+
+// Do is SendHttpRequest returning the response metadata along with the body.
+func (r *HttpRequester) Do(ctx context.Context, url string, data any, opts ...Option) (*Result, error) {
+	return r.run(ctx, url, data, nil, opts)
+}
+
+// Stream is Do passing the response body to consume as it is read, without
+// buffering it. Responses that must be kept, because they are cached or
+// captured, are buffered and then passed to consume. Streamed calls are not
+// coalesced.
+func (r *HttpRequester) Stream(ctx context.Context, url string, consume func(io.Reader) error, opts ...Option) (*Result, error) {
+	return r.run(ctx, url, nil, consume, opts)
+}
+
+func (r *HttpRequester) run(ctx context.Context, url string, data any, consume func(io.Reader) error, opts []Option) (*Result, error) {
+	body, err := encodeBody(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s, client := r.callSettings(opts)
+	endpoint := endpoint(s.BaseURL, url)
+	c := &call{
+		s:        s,
+		client:   client,
+		method:   http.MethodGet,
+		url:      url,
+		endpoint: endpoint,
+		body:     body,
+		ttl:      s.ttl(endpoint),
+	}
+	ctx, span := startSpan(ctx, c)
+	start := time.Now()
+	var res *Result
+	if consume != nil && !c.cacheable() && s.Capture == nil {
+		c.consume = consume
+		res, err = r.send(ctx, c)
+	} else {
+		res, err = r.do(ctx, c)
+		if err == nil && consume != nil {
+			if err = consume(bytes.NewReader(res.Body)); err != nil {
+				res = nil
 			}
-			buf = bytes.NewBuffer(b)
+		}
+	}
+	latency := time.Since(start)
+	r.logCall(ctx, c, res, err, latency)
+	r.observeCall(c, res, err, latency)
+	endSpan(span, res, err)
+	if err == nil && s.Capture != nil {
+		*s.Capture = *res
+	}
+	return res, err
+}
+
+func (r *HttpRequester) do(ctx context.Context, c *call) (*Result, error) {
+	s := c.s
+	if !c.cacheable() {
+		return r.coalescedFetch(ctx, c)
+	}
+
+	now := time.Now()
+	cached, ok := s.Cache.Get(c.url)
+	if ok && !s.NoCache {
+		if !cached.Expired(now) {
+			return cachedResult(c.url, cached, now, false), nil
+		}
+		if s.servesStale(cached, now) {
+			r.revalidate(ctx, c)
+			return cachedResult(c.url, cached, now, true), nil
 		}
 	}
 
-	client := &http.Client{}
+	return r.coalescedFetch(ctx, c)
+}
+
+func encodeBody(data any) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if reader, ok := data.(io.Reader); ok {
+		b, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		return b, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	return b, nil
+}
+
+// coalescedFetch is fetch sharing a single request between concurrent
+// identical calls.
+func (r *HttpRequester) coalescedFetch(ctx context.Context, c *call) (*Result, error) {
+	if !isIdempotent(c.method) || c.body != nil {
+		return r.fetch(ctx, c)
+	}
+	return r.flights.do(ctx, c.method+" "+c.url, func(ctx context.Context) (*Result, error) {
+		return r.fetch(ctx, c)
+	})
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, buf)
+// fetch sends the request and stores the response in the cache.
+func (r *HttpRequester) fetch(ctx context.Context, c *call) (*Result, error) {
+	res, err := r.send(ctx, c)
 	if err != nil {
-		return nil, fmt.Errorf("http.NewRequestWithContext: %v", err)
+		return nil, err
+	}
+	if c.cacheable() {
+		c.s.Cache.Set(c.url, &cache.Entry{
+			Body:     res.Body,
+			Header:   res.Header,
+			StoredAt: res.FetchedAt,
+			Expires:  res.FetchedAt.Add(c.ttl),
+		})
+	}
+	return res, nil
+}
+
+func (r *HttpRequester) send(ctx context.Context, c *call) (_ *Result, err error) {
+	var buf io.Reader = nil
+	if c.body != nil {
+		buf = bytes.NewReader(c.body)
+	}
+
+	ctx, info := withRequestInfo(ctx, c.endpoint)
+	defer func() {
+		if err != nil {
+			err = &attemptsError{err: err, attempts: int(info.attempts.Load())}
+		}
+	}()
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, buf)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	for k, v := range c.s.Header {
+		req.Header[k] = v
+	}
+	if c.s.UserAgent != "" {
+		req.Header.Set("User-Agent", c.s.UserAgent)
 	}
 	req.Header.Set("api_key", r.apiKey)
+	injectTraceContext(ctx, c.s, req.Header)
 
-	resp, err := client.Do(req)
+	start := time.Now()
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request: %v", err)
+		return nil, fmt.Errorf("request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("invalid http status: %d", resp.StatusCode)
+	res := &Result{
+		Header:     resp.Header,
+		StatusCode: resp.StatusCode,
+		URL:        c.url,
+		FetchedAt:  start,
+		Attempts:   int(info.attempts.Load()),
 	}
+	if resp.StatusCode == http.StatusOK && c.consume != nil {
+		counter := &countingReader{r: resp.Body}
+		if err := c.consume(counter); err != nil {
+			return nil, err
+		}
+		res.Size = counter.n
+		res.Latency = time.Since(start)
+		return res, nil
+	}
+
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("ioutil.ReadAll: %v", err)
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, c.endpoint, responseBody)
+	}
+	res.Body = responseBody
+	res.Size = int64(len(responseBody))
+	res.Latency = time.Since(start)
+	return res, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// RemainingDailyBudget returns the number of calls left in today's
+// client-side budget, or -1 if there is no daily limit.
+func (r *HttpRequester) RemainingDailyBudget() int {
+	return r.limiter.remaining()
+}
+
+// endpoint returns the WMATA endpoint of a request URL, i.e. the URL path
+// relative to the base URL without query parameters.
+func endpoint(baseURL, rawURL string) string {
+	endpoint := strings.TrimPrefix(rawURL, baseURL)
+	endpoint = strings.TrimPrefix(endpoint, "/")
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
 	}
-	return responseBody, nil
+	return endpoint
 }
 
-func GenerateUrl(suburl string, params map[string]string) (string, error) {
+// GenerateUrl returns the URL of suburl, relative to the base URL of the call
+// configured by opts.
+func (r *HttpRequester) GenerateUrl(suburl string, params map[string]string, opts ...Option) (string, error) {
+	baseURL := r.settings.BaseURL
+	if len(opts) > 0 {
+		s := r.settings
+		for _, opt := range opts {
+			opt(&s)
+		}
+		baseURL = s.BaseURL
+	}
 	base, err := url.Parse(baseURL)
 	if err != nil {
-		return "", fmt.Errorf("url.Parse (%s): %v", baseURL, err)
+		return "", fmt.Errorf("url.Parse (%s): %w", baseURL, err)
 	}
 
 	queryParams := url.Values{}
 	for k, v := range params {
-		queryParams.Add(k, v)
+		if v != "" {
+			queryParams.Add(k, v)
+		}
 	}
 	encodedQuery := queryParams.Encode()
 
+	baseStr := base.String()
+	if !strings.HasSuffix(baseStr, "/") {
+		baseStr += "/"
+	}
 	if encodedQuery != "" {
-		return base.String() + suburl + "?" + encodedQuery, nil
+		return baseStr + suburl + "?" + encodedQuery, nil
 	}
-	return base.String() + suburl, nil
+	return baseStr + suburl, nil
 }
@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveParam matches query parameter and header names whose values must
+// never be logged.
+var sensitiveParam = regexp.MustCompile(`(?i)(api[_-]?key|key|token|secret|password|signature|auth)`)
+
+// redact removes the API key from s.
+func (r *HttpRequester) redact(s string) string {
+	if r.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, r.apiKey, redacted)
+}
+
+func (r *HttpRequester) redactQuery(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	for k := range query {
+		if sensitiveParam.MatchString(k) {
+			query[k] = []string{redacted}
+		}
+	}
+	return r.redact(query.Encode())
+}
+
+func (r *HttpRequester) redactHeader(s Settings) http.Header {
+	header := s.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if s.UserAgent != "" {
+		header.Set("User-Agent", s.UserAgent)
+	}
+	header.Set("api_key", redacted)
+	for k, v := range header {
+		if sensitiveParam.MatchString(k) {
+			header[k] = []string{redacted}
+			continue
+		}
+		for i := range v {
+			v[i] = r.redact(v[i])
+		}
+	}
+	return header
+}
+
+// logCall logs the outcome of a call. Bodies and request headers are only
+// logged at debug level.
+func (r *HttpRequester) logCall(ctx context.Context, c *call, res *Result, err error, latency time.Duration) {
+	logger := c.s.Logger
+	if logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", c.method),
+		slog.String("endpoint", c.endpoint),
+		slog.String("query", r.redactQuery(c.url)),
+		slog.Duration("latency", latency),
+	}
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "wmata request failed", append(attrs, slog.String("error", r.redact(err.Error())))...)
+		return
+	}
+	attrs = append(attrs,
+		slog.Int("status", res.StatusCode),
+		slog.Int64("bytes", res.Size),
+		slog.Bool("cache_hit", res.CacheHit),
+	)
+	if res.Stale {
+		attrs = append(attrs, slog.Bool("stale", true), slog.Duration("age", res.Age))
+	}
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs,
+			slog.Any("request_headers", r.redactHeader(c.s)),
+			slog.String("body", r.redact(string(res.Body))),
+		)
+		logger.LogAttrs(ctx, slog.LevelDebug, "wmata request", attrs...)
+		return
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "wmata request", attrs...)
+}
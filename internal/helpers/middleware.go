@@ -0,0 +1,138 @@
+package helpers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Middleware wraps the transport used to send every request to WMATA.
+// Middlewares run in the order they are configured, the first one seeing the
+// request first.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// requestInfo is attached to the context of every request sent to WMATA.
+type requestInfo struct {
+	endpoint string
+	attempts atomic.Int32
+}
+
+type requestInfoKey struct{}
+
+// RequestEndpoint returns the WMATA endpoint a request is sent to, e.g.
+// "Rail.svc/json/jStations".
+func RequestEndpoint(req *http.Request) string {
+	if info, ok := req.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		return info.endpoint
+	}
+	return strings.TrimPrefix(req.URL.Path, "/")
+}
+
+func withRequestInfo(ctx context.Context, endpoint string) (context.Context, *requestInfo) {
+	info := &requestInfo{endpoint: endpoint}
+	return context.WithValue(ctx, requestInfoKey{}, info), info
+}
+
+// countAttempt records that a request is about to be sent to WMATA and
+// returns its attempt number.
+func countAttempt(req *http.Request) int {
+	if info, ok := req.Context().Value(requestInfoKey{}).(*requestInfo); ok {
+		return int(info.attempts.Add(1))
+	}
+	return 1
+}
+
+// chain wraps base with the configured middlewares and the built-in ones.
+// The built-in middlewares run last, so that user middlewares see one request
+// per call rather than one per attempt.
+func (r *HttpRequester) chain(s Settings, base http.RoundTripper) http.RoundTripper {
+	rt := base
+	if s.Recorder != nil {
+		rt = recorderMiddleware(s.Recorder)(rt)
+	}
+	rt = limiterMiddleware(r.limiter, s.FailFast, s.Observer)(rt)
+	rt = RetryMiddleware(s.Retry)(rt)
+	for i := len(s.Middlewares) - 1; i >= 0; i-- {
+		rt = s.Middlewares[i](rt)
+	}
+	return rt
+}
+
+func limiterMiddleware(limiter *rateLimiter, failFast bool, observer Observer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			if err := limiter.wait(req.Context(), failFast); err != nil {
+				return nil, err
+			}
+			attempt := countAttempt(req)
+			if observer != nil {
+				endpoint, _ := normalizeEndpoint(RequestEndpoint(req))
+				observer.ObserveAttempt(endpoint, attempt, time.Since(start))
+				observer.ObserveRemainingBudget(limiter.remaining())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+func recorderMiddleware(recorder CallRecorder) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				endpoint, _ := normalizeEndpoint(RequestEndpoint(req))
+				recorder.RecordCall(endpoint)
+			}
+			return resp, err
+		})
+	}
+}
+
+// LoggingMiddleware logs the endpoint, status and latency of every request.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				logger.Printf("%s %s: %v (%s)", req.Method, RequestEndpoint(req), err, time.Since(start))
+			} else {
+				logger.Printf("%s %s: %d (%s)", req.Method, RequestEndpoint(req), resp.StatusCode, time.Since(start))
+			}
+			return resp, err
+		})
+	}
+}
+
+// MetricsRecorder receives the outcome of every request.
+type MetricsRecorder interface {
+	ObserveRequest(endpoint string, statusCode int, latency time.Duration, err error)
+}
+
+// MetricsMiddleware reports the status and latency of every request to
+// recorder. The status code is 0 when err is set.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			recorder.ObserveRequest(RequestEndpoint(req), statusCode, time.Since(start), err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Observer receives the events needed to export SDK usage metrics. Endpoints
+// are normalized to exclude path parameters, e.g.
+// "StationPrediction.svc/json/GetPrediction".
+type Observer interface {
+	// ObserveCall is called once per call, whether it was served from the
+	// cache or from WMATA.
+	ObserveCall(endpoint string, statusCode int, latency time.Duration, cacheHit bool, err error)
+	// ObserveAttempt is called before every request sent to WMATA, attempt
+	// being 1 for the first request of a call and higher for retries.
+	ObserveAttempt(endpoint string, attempt int, rateLimitWait time.Duration)
+	// ObserveRemainingBudget is called with the remaining client-side daily
+	// budget after every request, or -1 if there is no daily limit.
+	ObserveRemainingBudget(remaining int)
+}
+
+// ErrorClass classifies an error returned by a call, for use as a metric
+// label.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrServerError):
+		return "server_error"
+	case errors.Is(err, ErrRateLimitExceeded):
+		return "client_rate_limited"
+	case errors.Is(err, ErrDailyQuotaExhausted):
+		return "quota_exhausted"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return "client_error"
+	}
+	return "network"
+}
+
+func (r *HttpRequester) observeCall(c *call, res *Result, err error, latency time.Duration) {
+	if c.s.Observer == nil {
+		return
+	}
+	endpoint, _ := normalizeEndpoint(c.endpoint)
+	statusCode, cacheHit := 0, false
+	if res != nil {
+		statusCode, cacheHit = res.StatusCode, res.CacheHit
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		statusCode = apiErr.StatusCode
+	}
+	c.s.Observer.ObserveCall(endpoint, statusCode, latency, cacheHit, err)
+}
@@ -0,0 +1,194 @@
+package helpers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Settings holds the configuration shared by every call made through an
+// HttpRequester.
+type Settings struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+	// Timeout overrides the timeout of the HTTP client.
+	Timeout   time.Duration
+	UserAgent string
+	Header    http.Header
+	Retry     RetryPolicy
+	// RateLimit is only read when the requester is created.
+	RateLimit RateLimit
+	// FailFast makes calls fail with ErrRateLimitExceeded instead of waiting
+	// when the client-side rate limit is reached.
+	FailFast bool
+	// Recorder is notified of every request sent to WMATA.
+	Recorder CallRecorder
+	Cache    cache.Cache
+	// TTLs overrides the default cache TTL of endpoints.
+	TTLs map[string]time.Duration
+	// NoCache skips the cache lookup; the response is still stored.
+	NoCache bool
+	// StaleWhileRevalidate serves expired cache entries no older than
+	// MaxStale, if set, while refreshing them in the background, so they are
+	// still served while WMATA fails.
+	StaleWhileRevalidate bool
+	MaxStale             time.Duration
+	Middlewares          []Middleware
+	Logger               *slog.Logger
+	// TracerProvider and Propagator default to the global ones.
+	TracerProvider trace.TracerProvider
+	Propagator     propagation.TextMapPropagator
+	Observer       Observer
+	// Capture receives the metadata of the response to a call.
+	Capture *Result
+}
+
+// CallRecorder accounts for the calls made against the API key.
+type CallRecorder interface {
+	RecordCall(endpoint string)
+}
+
+// Option configures the Settings of an HttpRequester. Options can also be
+// passed to a single call, in which case they only apply to that call.
+type Option func(*Settings)
+
+func defaultSettings() Settings {
+	return Settings{
+		BaseURL:   DefaultBaseURL,
+		Header:    http.Header{},
+		Retry:     DefaultRetryPolicy,
+		RateLimit: DefaultRateLimit,
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(s *Settings) {
+		s.BaseURL = baseURL
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Settings) {
+		s.HTTPClient = client
+	}
+}
+
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *Settings) {
+		s.Transport = transport
+	}
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(s *Settings) {
+		s.UserAgent = userAgent
+	}
+}
+
+func WithHeader(key, value string) Option {
+	return func(s *Settings) {
+		s.Header = s.Header.Clone()
+		s.Header.Add(key, value)
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *Settings) {
+		s.Retry = policy
+	}
+}
+
+func WithRateLimit(limit RateLimit) Option {
+	return func(s *Settings) {
+		s.RateLimit = limit
+	}
+}
+
+func WithFailFast(failFast bool) Option {
+	return func(s *Settings) {
+		s.FailFast = failFast
+	}
+}
+
+func WithCallRecorder(recorder CallRecorder) Option {
+	return func(s *Settings) {
+		s.Recorder = recorder
+	}
+}
+
+func WithCache(c cache.Cache) Option {
+	return func(s *Settings) {
+		s.Cache = c
+	}
+}
+
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return func(s *Settings) {
+		ttls := make(map[string]time.Duration, len(s.TTLs)+1)
+		for k, v := range s.TTLs {
+			ttls[k] = v
+		}
+		ttls[endpoint] = ttl
+		s.TTLs = ttls
+	}
+}
+
+func WithoutCache() Option {
+	return func(s *Settings) {
+		s.NoCache = true
+	}
+}
+
+func WithStaleWhileRevalidate(maxStale time.Duration) Option {
+	return func(s *Settings) {
+		s.StaleWhileRevalidate = true
+		s.MaxStale = maxStale
+	}
+}
+
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(s *Settings) {
+		s.Middlewares = append(s.Middlewares[:len(s.Middlewares):len(s.Middlewares)], middlewares...)
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Settings) {
+		s.Logger = logger
+	}
+}
+
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(s *Settings) {
+		s.TracerProvider = tp
+	}
+}
+
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(s *Settings) {
+		s.Propagator = propagator
+	}
+}
+
+func WithObserver(observer Observer) Option {
+	return func(s *Settings) {
+		s.Observer = observer
+	}
+}
+
+func WithResponseMeta(dst *Result) Option {
+	return func(s *Settings) {
+		s.Capture = dst
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Settings) {
+		s.Timeout = timeout
+	}
+}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidRequest is returned, before any network call, when the
+// parameters of a call are invalid.
+var ErrInvalidRequest = errors.New("wmata: invalid request")
+
+const dateLayout = "2006-01-02"
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRequest, fmt.Sprintf(format, args...))
+}
+
+// Required returns an error if a required parameter is empty.
+func Required(name, value string) error {
+	if value == "" {
+		return invalid("%s is required", name)
+	}
+	return nil
+}
+
+// ValidateLocation checks a search area. The area is optional: all three
+// values may be zero.
+func ValidateLocation(lat, lon float64, radius int) error {
+	if lat == 0 && lon == 0 && radius == 0 {
+		return nil
+	}
+	if lat < -90 || lat > 90 {
+		return invalid("Lat %v is not between -90 and 90", lat)
+	}
+	if lon < -180 || lon > 180 {
+		return invalid("Lon %v is not between -180 and 180", lon)
+	}
+	if lat == 0 && lon == 0 {
+		return invalid("Lat and Lon are required with Radius")
+	}
+	if radius <= 0 {
+		return invalid("Radius must be a positive number of meters, got %d", radius)
+	}
+	return nil
+}
+
+// Params builds the query parameters of a call, only keeping the ones that
+// are set.
+type Params map[string]string
+
+func (p Params) String(name, value string) Params {
+	if value != "" {
+		p[name] = value
+	}
+	return p
+}
+
+func (p Params) Location(lat, lon float64, radius int) Params {
+	if lat == 0 && lon == 0 && radius == 0 {
+		return p
+	}
+	p["Lat"] = strconv.FormatFloat(lat, 'f', -1, 64)
+	p["Lon"] = strconv.FormatFloat(lon, 'f', -1, 64)
+	p["Radius"] = strconv.Itoa(radius)
+	return p
+}
+
+// Date adds the service day of date, in Eastern time, as WMATA expects.
+func (p Params) Date(name string, date time.Time) Params {
+	if !date.IsZero() {
+		p[name] = date.In(Eastern).Format(dateLayout)
+	}
+	return p
+}
+
+func (p Params) Bool(name string, value bool) Params {
+	if value {
+		p[name] = "true"
+	}
+	return p
+}
@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRateLimitExceeded is returned in fail-fast mode when the client-side
+	// per-second budget is exhausted.
+	ErrRateLimitExceeded = errors.New("wmata: client rate limit exceeded")
+	// ErrDailyQuotaExhausted is returned when the client-side daily budget is
+	// exhausted. The budget resets at midnight Eastern.
+	ErrDailyQuotaExhausted = errors.New("wmata: daily quota exhausted")
+)
+
+// RateLimit configures the client-side token bucket. Zero values disable the
+// corresponding limit.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+	PerDay    int
+}
+
+// DefaultRateLimit matches the WMATA default tier.
+var DefaultRateLimit = RateLimit{
+	PerSecond: 10,
+	Burst:     10,
+	PerDay:    50000,
+}
+
+type rateLimiter struct {
+	limit RateLimit
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	day      time.Time
+	dayCount int
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &rateLimiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+	}
+}
+
+// wait takes one token, waiting for it to become available unless failFast
+// is set.
+func (l *rateLimiter) wait(ctx context.Context, failFast bool) error {
+	for {
+		delay, err := l.reserve(time.Now(), failFast)
+		if err != nil || delay == 0 {
+			return err
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before a
+// token is available.
+func (l *rateLimiter) reserve(now time.Time, failFast bool) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.resetDay(now)
+	if l.limit.PerDay > 0 && l.dayCount >= l.limit.PerDay {
+		return 0, ErrDailyQuotaExhausted
+	}
+
+	if l.limit.PerSecond > 0 {
+		if !l.last.IsZero() {
+			l.tokens += now.Sub(l.last).Seconds() * l.limit.PerSecond
+			if l.tokens > float64(l.limit.Burst) {
+				l.tokens = float64(l.limit.Burst)
+			}
+		}
+		l.last = now
+		if l.tokens < 1 {
+			if failFast {
+				return 0, ErrRateLimitExceeded
+			}
+			return time.Duration((1 - l.tokens) / l.limit.PerSecond * float64(time.Second)), nil
+		}
+		l.tokens--
+	}
+
+	l.dayCount++
+	return 0, nil
+}
+
+func (l *rateLimiter) resetDay(now time.Time) {
+	if day := ServiceDay(now); !day.Equal(l.day) {
+		l.day = day
+		l.dayCount = 0
+	}
+}
+
+// remaining returns the number of calls left in today's budget, or -1 if
+// there is no daily limit.
+func (l *rateLimiter) remaining() int {
+	if l.limit.PerDay <= 0 {
+		return -1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.resetDay(time.Now())
+	return l.limit.PerDay - l.dayCount
+}
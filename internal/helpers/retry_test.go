@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterBeyondBackoff(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name   string
+		policy RetryPolicy
+		ctx    func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name:   "max backoff",
+			policy: DefaultRetryPolicy,
+			ctx:    func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+		},
+		{
+			name:   "deadline",
+			policy: RetryPolicy{MaxAttempts: 3},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			requests.Store(0)
+			r := New("key",
+				WithBaseURL(srv.URL+"/"),
+				WithRateLimit(RateLimit{}),
+				WithRetryPolicy(tc.policy),
+				WithTimeout(time.Second),
+			)
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			start := time.Now()
+			_, err := Send(ctx, r, Lines, nil)
+			if !errors.Is(err, ErrRateLimited) {
+				t.Fatalf("err = %v, want ErrRateLimited", err)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("took %v, want no wait for Retry-After", elapsed)
+			}
+			if n := requests.Load(); n != 1 {
+				t.Errorf("got %d requests, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
+)
+
+// revalidateTimeout bounds background refreshes, which outlive the call that
+// triggered them.
+const revalidateTimeout = 30 * time.Second
+
+// servesStale reports whether an expired cache entry may still be served.
+func (s Settings) servesStale(e *cache.Entry, now time.Time) bool {
+	return s.StaleWhileRevalidate && (s.MaxStale <= 0 || now.Sub(e.StoredAt) <= s.MaxStale)
+}
+
+// revalidate refreshes the cache entry of c in the background, unless a
+// refresh is already in flight.
+func (r *HttpRequester) revalidate(ctx context.Context, c *call) {
+	if _, loaded := r.refreshing.LoadOrStore(c.url, struct{}{}); loaded {
+		return
+	}
+	go func() {
+		defer r.refreshing.Delete(c.url)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+		defer cancel()
+		_, _ = r.coalescedFetch(ctx, c)
+	}()
+}
+
+func cachedResult(url string, e *cache.Entry, now time.Time, stale bool) *Result {
+	res := &Result{
+		Body:       e.Body,
+		Header:     e.Header,
+		StatusCode: http.StatusOK,
+		URL:        url,
+		Size:       int64(len(e.Body)),
+		FetchedAt:  e.StoredAt,
+		CacheHit:   true,
+		Stale:      stale,
+	}
+	if stale {
+		res.Age = now.Sub(e.StoredAt)
+	}
+	return res
+}
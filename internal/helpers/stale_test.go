@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
+)
+
+// staleFixture is a requester serving stale entries for up to five minutes,
+// in front of a server answering with status.
+type staleFixture struct {
+	r        *HttpRequester
+	cache    cache.Cache
+	url      string
+	requests atomic.Int32
+	status   atomic.Int32
+}
+
+func newStaleFixture(t *testing.T) *staleFixture {
+	t.Helper()
+	f := &staleFixture{cache: cache.NewLRU(8)}
+	f.status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests.Add(1)
+		if status := int(f.status.Load()); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("fresh"))
+	}))
+	t.Cleanup(srv.Close)
+	f.r = New("key",
+		WithBaseURL(srv.URL+"/"),
+		WithRateLimit(RateLimit{}),
+		WithRetryPolicy(NoRetry),
+		WithCache(f.cache),
+		WithStaleWhileRevalidate(5*time.Minute),
+	)
+	url, err := Lines.url(f.r, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.url = url
+	return f
+}
+
+// store caches an entry fetched age ago that expired a second later.
+func (f *staleFixture) store(age time.Duration) {
+	storedAt := time.Now().Add(-age)
+	f.cache.Set(f.url, &cache.Entry{Body: []byte("cached"), StoredAt: storedAt, Expires: storedAt.Add(time.Second)})
+}
+
+// waitForRefresh waits until no background refresh is in flight.
+func (f *staleFixture) waitForRefresh(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := f.r.refreshing.Load(f.url); !ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (f *staleFixture) send(t *testing.T) (*Result, error) {
+	t.Helper()
+	return Send(context.Background(), f.r, Lines, nil)
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	f := newStaleFixture(t)
+	f.store(time.Minute)
+
+	res, err := f.send(t)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if !res.Stale || string(res.Body) != "cached" {
+		t.Errorf("got stale = %v, body %q; want the stale entry", res.Stale, res.Body)
+	}
+	if res.Age < time.Minute || res.Age > time.Minute+5*time.Second {
+		t.Errorf("age = %v, want about a minute", res.Age)
+	}
+
+	f.waitForRefresh(t)
+	if n := f.requests.Load(); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	res, err = f.send(t)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if res.Stale || string(res.Body) != "fresh" {
+		t.Errorf("got stale = %v, body %q; want the refreshed entry", res.Stale, res.Body)
+	}
+}
+
+func TestStaleServedWhileServerFails(t *testing.T) {
+	f := newStaleFixture(t)
+	f.status.Store(http.StatusServiceUnavailable)
+	f.store(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		res, err := f.send(t)
+		if err != nil {
+			t.Fatalf("call %d: Send: %v", i, err)
+		}
+		if !res.Stale || string(res.Body) != "cached" {
+			t.Errorf("call %d: got stale = %v, body %q; want the stale entry", i, res.Stale, res.Body)
+		}
+		f.waitForRefresh(t)
+	}
+	if n := f.requests.Load(); n != 2 {
+		t.Errorf("got %d requests, want a refresh per call", n)
+	}
+}
+
+func TestStaleBeyondMaxStale(t *testing.T) {
+	f := newStaleFixture(t)
+	f.store(10 * time.Minute)
+	res, err := f.send(t)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if res.Stale || string(res.Body) != "fresh" {
+		t.Errorf("got stale = %v, body %q; want a fresh response", res.Stale, res.Body)
+	}
+
+	f.status.Store(http.StatusServiceUnavailable)
+	f.store(10 * time.Minute)
+	if _, err := f.send(t); !errors.Is(err, ErrServerError) {
+		t.Errorf("Send: err = %v, want ErrServerError", err)
+	}
+}
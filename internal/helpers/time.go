@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+)
+
+// Eastern is the time zone WMATA operates in and reports times in.
+var Eastern = mustLoadLocation("America/New_York")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// ServiceDay returns the Eastern calendar day containing t.
+func ServiceDay(t time.Time) time.Time {
+	y, m, d := t.In(Eastern).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Eastern)
+}
+
+// ParseTimestamp parses a WMATA timestamp such as "2024-05-01T17:30:00" in
+// Eastern time. Schedules may write times past midnight on the service day as
+// hours of 24 or more, which carry over into the next day. An empty string
+// is the zero time.
+func ParseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	date, clock, ok := strings.Cut(s, "T")
+	if !ok {
+		return time.Time{}, fmt.Errorf("timestamp %q: missing time", s)
+	}
+	day, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("time.Parse: %w", err)
+	}
+	hour, minute, nanos, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp %q: %w", s, err)
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, hour, minute, 0, nanos, Eastern), nil
+}
+
+// parseClock parses hh:mm:ss with optional fractional seconds, returning the
+// seconds as nanoseconds. Hours may go up to 47.
+func parseClock(clock string) (hour, minute, nanos int, err error) {
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 {
+		return 0, 0, 0, errors.New("malformed time")
+	}
+	whole, frac, hasFrac := strings.Cut(parts[2], ".")
+	if len(frac) > 9 || (hasFrac && frac == "") {
+		return 0, 0, 0, errors.New("malformed time")
+	}
+	hour, err = parseDigits(parts[0])
+	if err == nil {
+		minute, err = parseDigits(parts[1])
+	}
+	var second int
+	if err == nil {
+		second, err = parseDigits(whole)
+	}
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if hour >= 48 || minute >= 60 || second >= 60 {
+		return 0, 0, 0, errors.New("time out of range")
+	}
+	nanos = second * int(time.Second)
+	if frac != "" {
+		fraction, err := parseDigits(frac)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for i := len(frac); i < 9; i++ {
+			fraction *= 10
+		}
+		nanos += fraction
+	}
+	return hour, minute, nanos, nil
+}
+
+// parseDigits parses a non-negative decimal number, unlike strconv.Atoi
+// rejecting signs.
+func parseDigits(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("malformed number %q", s)
+	}
+	return strconv.Atoi(s)
+}
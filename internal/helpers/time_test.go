@@ -0,0 +1,71 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	utc := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for _, tc := range []struct {
+		in   string
+		want time.Time
+	}{
+		{in: "", want: time.Time{}},
+		{in: "2024-05-01T17:30:00", want: utc("2024-05-01T21:30:00Z")},
+		{in: "2024-05-01T17:30:05.25", want: utc("2024-05-01T21:30:05.25Z")},
+		{in: "2024-01-15T08:05:09", want: utc("2024-01-15T13:05:09Z")},
+		// Hours past midnight carry over into the next day.
+		{in: "2024-05-03T24:00:00", want: utc("2024-05-04T04:00:00Z")},
+		{in: "2024-05-03T25:15:00", want: utc("2024-05-04T05:15:00Z")},
+		{in: "2024-12-31T26:00:00", want: utc("2025-01-01T07:00:00Z")},
+		// 01:30 happens twice on the fall-back day; the first one, still in
+		// daylight time, is used whichever way it is written.
+		{in: "2024-11-03T01:30:00", want: utc("2024-11-03T05:30:00Z")},
+		{in: "2024-11-02T25:30:00", want: utc("2024-11-03T05:30:00Z")},
+		{in: "2024-11-03T02:30:00", want: utc("2024-11-03T07:30:00Z")},
+		{in: "2024-11-02T23:30:00", want: utc("2024-11-03T03:30:00Z")},
+	} {
+		got, err := ParseTimestamp(tc.in)
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q): %v", tc.in, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseTimestamp(%q) = %v, want %v", tc.in, got, tc.want.In(Eastern))
+		}
+	}
+}
+
+func TestParseTimestampErrors(t *testing.T) {
+	for _, in := range []string{
+		"2024-05-01T17:30:00Z",
+		"2024-05-01T17:30:00-04:00",
+		"2024-05-01T17:30:00.",
+		"2024-05-01T17:30:00.1234567890",
+		"2024-05-01T17:30",
+		"2024-05-01T17:30:00:00",
+		"2024-05-01T17:3a:00",
+		"2024-05-01T-1:30:00",
+		"2024-05-01T+1:30:00",
+		"2024-05-01T17:30: 0",
+		"2024-05-01T48:00:00",
+		"2024-05-01T17:60:00",
+		"2024-05-01T17:30:60",
+		"2024-05-01 17:30:00",
+		"2024-13-01T17:30:00",
+		"2024-05-01",
+		"T17:30:00",
+	} {
+		if got, err := ParseTimestamp(in); err == nil {
+			t.Errorf("ParseTimestamp(%q) = %v, want error", in, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/thompsonja/wmata-go"
+
+// normalizeEndpoint strips the path parameters from an endpoint, returning
+// them as attributes.
+func normalizeEndpoint(endpoint string) (string, []attribute.KeyValue) {
+	e, params := lookupEndpoint(endpoint)
+	if e == nil {
+		return endpoint, nil
+	}
+	var attrs []attribute.KeyValue
+	for k, v := range params {
+		attrs = append(attrs, attribute.String("wmata.param."+k, v))
+	}
+	return e.Name(), attrs
+}
+
+func (s Settings) tracer() trace.Tracer {
+	tp := s.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+func (s Settings) propagator() propagation.TextMapPropagator {
+	if s.Propagator != nil {
+		return s.Propagator
+	}
+	return otel.GetTextMapPropagator()
+}
+
+// startSpan starts the span of a call, with the call parameters as
+// attributes.
+func startSpan(ctx context.Context, c *call) (context.Context, trace.Span) {
+	name, attrs := normalizeEndpoint(c.endpoint)
+	attrs = append(attrs,
+		attribute.String("wmata.endpoint", c.endpoint),
+		attribute.String("http.request.method", c.method),
+	)
+	if u, err := url.Parse(c.url); err == nil {
+		for k, v := range u.Query() {
+			if !sensitiveParam.MatchString(k) {
+				attrs = append(attrs, attribute.String("wmata.param."+k, strings.Join(v, ",")))
+			}
+		}
+	}
+	return c.s.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endSpan(span trace.Span, res *Result, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+		}
+		var attemptsErr *attemptsError
+		if errors.As(err, &attemptsErr) {
+			span.SetAttributes(attribute.Int("wmata.retry_count", max(attemptsErr.attempts-1, 0)))
+		}
+	}
+	if res != nil {
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", res.StatusCode),
+			attribute.Int("wmata.retry_count", max(res.Attempts-1, 0)),
+			attribute.Bool("wmata.cache_hit", res.CacheHit),
+			attribute.Bool("wmata.stale", res.Stale),
+		)
+	}
+	span.End()
+}
+
+// injectTraceContext propagates the trace context of ctx to WMATA through the
+// request headers.
+func injectTraceContext(ctx context.Context, s Settings, header http.Header) {
+	s.propagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
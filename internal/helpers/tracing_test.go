@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/thompsonja/wmata-go/pkg/cache"
+)
+
+func TestTracing(t *testing.T) {
+	var requests atomic.Int32
+	traceparents := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+		// Fail the first attempt so that the call is retried once.
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"Trains":[]}`))
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	r := New("key",
+		WithBaseURL(srv.URL+"/"),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithPropagator(propagation.TraceContext{}),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}),
+		WithCache(cache.NewLRU(8)),
+	)
+	params := map[string]string{"StationCode": "A01"}
+	for i := 0; i < 2; i++ {
+		if _, err := Send(context.Background(), r, RailPredictions, params); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	for i, want := range []struct {
+		retries  int64
+		cacheHit bool
+	}{
+		{retries: 1, cacheHit: false},
+		{retries: 0, cacheHit: true},
+	} {
+		span := spans[i]
+		if span.Name() != "StationPrediction.svc/json/GetPrediction" {
+			t.Errorf("span %d: name = %q", i, span.Name())
+		}
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("span %d: kind = %v, want client", i, span.SpanKind())
+		}
+		attrs := attribute.NewSet(span.Attributes()...)
+		for key, value := range map[attribute.Key]attribute.Value{
+			"wmata.param.StationCode":   attribute.StringValue("A01"),
+			"wmata.endpoint":            attribute.StringValue("StationPrediction.svc/json/GetPrediction/A01"),
+			"http.request.method":       attribute.StringValue(http.MethodGet),
+			"http.response.status_code": attribute.IntValue(http.StatusOK),
+			"wmata.retry_count":         attribute.Int64Value(want.retries),
+			"wmata.cache_hit":           attribute.BoolValue(want.cacheHit),
+		} {
+			if got, ok := attrs.Value(key); !ok || got != value {
+				t.Errorf("span %d: %s = %v, want %v", i, key, got.Emit(), value.Emit())
+			}
+		}
+	}
+
+	// Both attempts of the first call carry its trace context; the second
+	// call is served from the cache.
+	close(traceparents)
+	want := spans[0].SpanContext().TraceID().String()
+	n := 0
+	for traceparent := range traceparents {
+		n++
+		carrier := propagation.HeaderCarrier(http.Header{"Traceparent": {traceparent}})
+		got := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+		if got.TraceID().String() != want {
+			t.Errorf("traceparent %q: trace ID = %s, want %s", traceparent, got.TraceID(), want)
+		}
+	}
+	if n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+
+	// A call that fails on every attempt still reports its retries.
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	_, err := Send(context.Background(), r, RailPredictions, map[string]string{"StationCode": "B01"}, WithBaseURL(failing.URL+"/"))
+	if !errors.Is(err, ErrServerError) {
+		t.Fatalf("Send: err = %v, want ErrServerError", err)
+	}
+	spans = recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	attrs := attribute.NewSet(spans[2].Attributes()...)
+	for key, value := range map[attribute.Key]attribute.Value{
+		"http.response.status_code": attribute.IntValue(http.StatusServiceUnavailable),
+		"wmata.retry_count":         attribute.Int64Value(1),
+	} {
+		if got, ok := attrs.Value(key); !ok || got != value {
+			t.Errorf("failed span: %s = %v, want %v", key, got.Emit(), value.Emit())
+		}
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Errorf("failed span: status = %v, want error", spans[2].Status().Code)
+	}
+}
@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds a whole call, including retries, unless WithTimeout or
+// WithHTTPClient is used.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultTransport is shared by every requester that isn't given its own
+// client or transport, so that all services reuse the same pool of
+// connections to WMATA.
+var DefaultTransport http.RoundTripper = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2: true,
+	// Every request goes to the same host, so the per-host limit is what
+	// matters. The net/http default of 2 forces new connections as soon as a
+	// few goroutines share a client.
+	MaxIdleConns:          64,
+	MaxIdleConnsPerHost:   32,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 15 * time.Second,
+	ExpectContinueTimeout: time.Second,
+	TLSClientConfig: &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ClientSessionCache: tls.NewLRUClientSessionCache(64),
+	},
+}
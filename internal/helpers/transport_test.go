@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func BenchmarkTransport(b *testing.B) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Lines":[]}`))
+	}))
+	defer srv.Close()
+	roots := srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	// shared is DefaultTransport trusting the test server, and netDefault
+	// the same for http.DefaultTransport.
+	shared := DefaultTransport.(*http.Transport).Clone()
+	shared.TLSClientConfig.RootCAs = roots
+	defer shared.CloseIdleConnections()
+	netDefault := http.DefaultTransport.(*http.Transport).Clone()
+	netDefault.TLSClientConfig = &tls.Config{RootCAs: roots}
+	defer netDefault.CloseIdleConnections()
+
+	ctx := context.Background()
+	call := func(opts ...Option) error {
+		opts = append(opts, WithBaseURL(srv.URL+"/"), WithRateLimit(RateLimit{}))
+		_, err := Send(ctx, New("key", opts...), Lines, nil)
+		return err
+	}
+
+	b.Run("transport per request", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}
+			err := call(WithHTTPClient(&http.Client{Transport: transport}))
+			transport.CloseIdleConnections()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	// A new http.Client per request on http.DefaultTransport, as requests
+	// used to be sent.
+	b.Run("client per request", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := call(WithHTTPClient(&http.Client{Transport: netDefault})); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("client per request parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := call(WithHTTPClient(&http.Client{Transport: netDefault})); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+	b.Run("requester per package", func(b *testing.B) {
+		requesters := make([]*HttpRequester, 7)
+		for i := range requesters {
+			requesters[i] = New("key", WithBaseURL(srv.URL+"/"), WithRateLimit(RateLimit{}), WithTransport(shared))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := Send(ctx, requesters[i%len(requesters)], Lines, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("shared transport", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := call(WithTransport(shared)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("shared transport parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := call(WithTransport(shared)); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+}
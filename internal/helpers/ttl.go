@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"strings"
+	"time"
+)
+
+// ttl returns the cache TTL of an endpoint, preferring configured TTLs over
+// the defaults of the endpoint table. Configured keys ending in a slash match
+// every endpoint below them.
+func (s Settings) ttl(endpoint string) time.Duration {
+	if ttl, ok := s.TTLs[endpoint]; ok {
+		return ttl
+	}
+	e, _ := lookupEndpoint(endpoint)
+	if e != nil {
+		if ttl, ok := s.TTLs[e.Name()]; ok {
+			return ttl
+		}
+	}
+	for prefix, ttl := range s.TTLs {
+		if strings.HasSuffix(prefix, "/") && strings.HasPrefix(endpoint, prefix) {
+			return ttl
+		}
+	}
+	if e != nil {
+		return e.TTL
+	}
+	return 0
+}
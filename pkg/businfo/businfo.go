@@ -2,24 +2,25 @@ package businfo
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"time"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type BusPosition struct {
-	DateTime      string  `json:"DateTime"`
-	Deviation     int     `json:"Deviation"`
-	DirectionText string  `json:"DirectionText"`
-	Lat           float64 `json:"Lat"`
-	Lon           float64 `json:"Lon"`
-	RouteID       string  `json:"RouteID"`
-	TripEndTime   string  `json:"TripEndTime"`
-	TripHeadsign  string  `json:"TripHeadsign"`
-	TripID        string  `json:"TripID"`
-	TripStartTime string  `json:"TripStartTime"`
-	VehicleID     string  `json:"VehicleID"`
+	DateTime      string      `json:"DateTime"`
+	Deviation     int         `json:"Deviation"`
+	DirectionText string      `json:"DirectionText"`
+	Lat           float64     `json:"Lat"`
+	Lon           float64     `json:"Lon"`
+	RouteID       ids.RouteID `json:"RouteID"`
+	TripEndTime   string      `json:"TripEndTime"`
+	TripHeadsign  string      `json:"TripHeadsign"`
+	TripID        string      `json:"TripID"`
+	TripStartTime string      `json:"TripStartTime"`
+	VehicleID     string      `json:"VehicleID"`
 }
 
 type BusPositionsResponse struct {
@@ -33,10 +34,10 @@ type Direction struct {
 }
 
 type PathDetailsResponse struct {
-	Direction0 Direction `json:"Direction0"`
-	Direction1 Direction `json:"Direction1"`
-	Name       string    `json:"Name"`
-	RouteID    string    `json:"RouteID"`
+	Direction0 Direction   `json:"Direction0"`
+	Direction1 Direction   `json:"Direction1"`
+	Name       string      `json:"Name"`
+	RouteID    ids.RouteID `json:"RouteID"`
 }
 
 type Shape struct {
@@ -46,9 +47,9 @@ type Shape struct {
 }
 
 type Route struct {
-	RouteID         string `json:"RouteID"`
-	Name            string `json:"Name"`
-	LineDescription string `json:"LineDescription"`
+	RouteID         ids.RouteID `json:"RouteID"`
+	Name            string      `json:"Name"`
+	LineDescription string      `json:"LineDescription"`
 }
 
 type RoutesResponse struct {
@@ -56,20 +57,20 @@ type RoutesResponse struct {
 }
 
 type StopTime struct {
-	StopID   string `json:"StopID"`
-	StopName string `json:"StopName"`
-	StopSeq  int    `json:"StopSeq"`
-	Time     string `json:"Time"`
+	StopID   ids.StopID `json:"StopID"`
+	StopName string     `json:"StopName"`
+	StopSeq  int        `json:"StopSeq"`
+	Time     string     `json:"Time"`
 }
 
 type Trip struct {
-	EndTime           string     `json:"EndTime"`
-	RouteID           string     `json:"RouteID"`
-	StartTime         string     `json:"StartTime"`
-	StopTimes         []StopTime `json:"StopTimes"`
-	TripDirectionText string     `json:"TripDirectionText"`
-	TripHeadsign      string     `json:"TripHeadsign"`
-	TripID            string     `json:"TripID"`
+	EndTime           string      `json:"EndTime"`
+	RouteID           ids.RouteID `json:"RouteID"`
+	StartTime         string      `json:"StartTime"`
+	StopTimes         []StopTime  `json:"StopTimes"`
+	TripDirectionText string      `json:"TripDirectionText"`
+	TripHeadsign      string      `json:"TripHeadsign"`
+	TripID            string      `json:"TripID"`
 }
 
 type ScheduleResponse struct {
@@ -78,14 +79,14 @@ type ScheduleResponse struct {
 }
 
 type ScheduleArrival struct {
-	DirectionNum      string `json:"DirectionNum"`
-	EndTime           string `json:"EndTime"`
-	RouteID           string `json:"RouteID"`
-	ScheduleTime      string `json:"ScheduleTime"`
-	StartTime         string `json:"StartTime"`
-	TripDirectionText string `json:"TripDirectionText"`
-	TripHeadsign      string `json:"TripHeadsign"`
-	TripID            string `json:"TripID"`
+	DirectionNum      string      `json:"DirectionNum"`
+	EndTime           string      `json:"EndTime"`
+	RouteID           ids.RouteID `json:"RouteID"`
+	ScheduleTime      string      `json:"ScheduleTime"`
+	StartTime         string      `json:"StartTime"`
+	TripDirectionText string      `json:"TripDirectionText"`
+	TripHeadsign      string      `json:"TripHeadsign"`
+	TripID            string      `json:"TripID"`
 }
 
 type ScheduleArrivalsResponse struct {
@@ -93,131 +94,211 @@ type ScheduleArrivalsResponse struct {
 }
 
 type Stop struct {
-	Lat    float64  `json:"Lat"`
-	Lon    float64  `json:"Lon"`
-	Name   string   `json:"Name"`
-	Routes []string `json:"Routes"`
-	StopID string   `json:"StopID"`
+	Lat    float64       `json:"Lat"`
+	Lon    float64       `json:"Lon"`
+	Name   string        `json:"Name"`
+	Routes []ids.RouteID `json:"Routes"`
+	StopID ids.StopID    `json:"StopID"`
 }
 
 type StopsResponse struct {
 	Stops []Stop `json:"Stops"`
 }
 
-type API struct {
-	requester *helpers.HttpRequester
+// ParsedDateTime returns DateTime in Eastern time, or the zero time if it is
+// unset.
+func (p BusPosition) ParsedDateTime() (time.Time, error) {
+	return helpers.ParseTimestamp(p.DateTime)
 }
 
-func New(apiKey string) *API {
-	return &API{
-		requester: helpers.New(apiKey),
-	}
+// ParsedTripStartTime returns TripStartTime in Eastern time, or the zero time
+// if it is unset.
+func (p BusPosition) ParsedTripStartTime() (time.Time, error) {
+	return helpers.ParseTimestamp(p.TripStartTime)
 }
 
-func (a *API) GetBusPositions(ctx context.Context, routeID, lat, lon, radius string) (*BusPositionsResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jBusPositions", map[string]string{"RouteID": routeID, "Lat": lat, "Lon": lon, "Radius": radius})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+// ParsedTripEndTime returns TripEndTime in Eastern time, or the zero time if
+// it is unset.
+func (p BusPosition) ParsedTripEndTime() (time.Time, error) {
+	return helpers.ParseTimestamp(p.TripEndTime)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response BusPositionsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+// ParsedTime returns Time in Eastern time, or the zero time if it is unset.
+func (s StopTime) ParsedTime() (time.Time, error) {
+	return helpers.ParseTimestamp(s.Time)
 }
 
-func (a *API) GetPathDetails(ctx context.Context, routeID, date string) (*PathDetailsResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jRouteDetails", map[string]string{"RouteID": routeID, "Date": date})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+// ParsedStartTime returns StartTime in Eastern time, or the zero time if it is
+// unset.
+func (t Trip) ParsedStartTime() (time.Time, error) {
+	return helpers.ParseTimestamp(t.StartTime)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response PathDetailsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+// ParsedEndTime returns EndTime in Eastern time, or the zero time if it is
+// unset.
+func (t Trip) ParsedEndTime() (time.Time, error) {
+	return helpers.ParseTimestamp(t.EndTime)
 }
 
-func (a *API) GetRoutes(ctx context.Context) (*RoutesResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jRoutes", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+// ParsedScheduleTime returns ScheduleTime in Eastern time, or the zero time if
+// it is unset.
+func (a ScheduleArrival) ParsedScheduleTime() (time.Time, error) {
+	return helpers.ParseTimestamp(a.ScheduleTime)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response RoutesResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+// ParsedStartTime returns StartTime in Eastern time, or the zero time if it is
+// unset.
+func (a ScheduleArrival) ParsedStartTime() (time.Time, error) {
+	return helpers.ParseTimestamp(a.StartTime)
 }
 
-func (a *API) GetSchedule(ctx context.Context, routeID, date, includingVariations string) (*ScheduleResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jRouteSchedule", map[string]string{"RouteID": routeID, "Date": date, "IncludingVariations": includingVariations})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+// ParsedEndTime returns EndTime in Eastern time, or the zero time if it is
+// unset.
+func (a ScheduleArrival) ParsedEndTime() (time.Time, error) {
+	return helpers.ParseTimestamp(a.EndTime)
+}
+
+type BusPositionsRequest struct {
+	RouteID ids.RouteID
+	// Lat, Lon and Radius, in meters, restrict the positions to an area.
+	Lat    float64
+	Lon    float64
+	Radius int
+}
+
+func (r BusPositionsRequest) Validate() error {
+	return helpers.ValidateLocation(r.Lat, r.Lon, r.Radius)
+}
+
+func (r BusPositionsRequest) params() helpers.Params {
+	return helpers.Params{}.String("RouteID", string(r.RouteID)).Location(r.Lat, r.Lon, r.Radius)
+}
+
+type PathDetailsRequest struct {
+	RouteID ids.RouteID
+	// Date defaults to today.
+	Date time.Time
+}
+
+func (r PathDetailsRequest) Validate() error {
+	return helpers.Required("RouteID", string(r.RouteID))
+}
+
+func (r PathDetailsRequest) params() helpers.Params {
+	return helpers.Params{}.String("RouteID", string(r.RouteID)).Date("Date", r.Date)
+}
+
+type ScheduleRequest struct {
+	RouteID ids.RouteID
+	// Date defaults to today.
+	Date                time.Time
+	IncludingVariations bool
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
+func (r ScheduleRequest) Validate() error {
+	return helpers.Required("RouteID", string(r.RouteID))
+}
+
+func (r ScheduleRequest) params() helpers.Params {
+	return helpers.Params{}.String("RouteID", string(r.RouteID)).Date("Date", r.Date).Bool("IncludingVariations", r.IncludingVariations)
+}
+
+type ScheduleAtStopRequest struct {
+	StopID ids.StopID
+	// Date defaults to today.
+	Date                time.Time
+	IncludingVariations bool
+}
+
+func (r ScheduleAtStopRequest) Validate() error {
+	return helpers.Required("StopID", string(r.StopID))
+}
+
+func (r ScheduleAtStopRequest) params() helpers.Params {
+	return helpers.Params{}.String("StopID", string(r.StopID)).Date("Date", r.Date).Bool("IncludingVariations", r.IncludingVariations)
+}
+
+type StopsRequest struct {
+	// Lat, Lon and Radius, in meters, restrict the stops to an area.
+	Lat    float64
+	Lon    float64
+	Radius int
+}
+
+func (r StopsRequest) Validate() error {
+	return helpers.ValidateLocation(r.Lat, r.Lon, r.Radius)
+}
+
+func (r StopsRequest) params() helpers.Params {
+	return helpers.Params{}.Location(r.Lat, r.Lon, r.Radius)
+}
+
+type API struct {
+	requester *helpers.HttpRequester
+}
+
+func New(apiKey string, opts ...option.Option) *API {
+	return &API{
+		requester: helpers.New(apiKey, opts...),
 	}
-	var response ScheduleResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+}
+
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
-	return &response, nil
 }
 
-func (a *API) GetScheduleAtStop(ctx context.Context, stopID, date, includingVariations string) (*ScheduleArrivalsResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jStopSchedule", map[string]string{"StopID": stopID, "Date": date})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func (a *API) GetBusPositions(ctx context.Context, req BusPositionsRequest, opts ...option.Option) (*BusPositionsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
+	return helpers.Get[BusPositionsResponse](ctx, a.requester, helpers.BusPositions, req.params(), opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
+func (a *API) GetPathDetails(ctx context.Context, req PathDetailsRequest, opts ...option.Option) (*PathDetailsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	var response ScheduleArrivalsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+	return helpers.Get[PathDetailsResponse](ctx, a.requester, helpers.RouteDetails, req.params(), opts...)
+}
+
+func (a *API) GetRoutes(ctx context.Context, opts ...option.Option) (*RoutesResponse, error) {
+	return helpers.Get[RoutesResponse](ctx, a.requester, helpers.Routes, nil, opts...)
+}
+
+func (a *API) GetSchedule(ctx context.Context, req ScheduleRequest, opts ...option.Option) (*ScheduleResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	return &response, nil
+	return helpers.Get[ScheduleResponse](ctx, a.requester, helpers.RouteSchedule, req.params(), opts...)
 }
 
-func (a *API) GetStops(ctx context.Context, lat, lon, radius string) (*StopsResponse, error) {
-	url, err := helpers.GenerateUrl("Bus.svc/json/jStops", map[string]string{"Lat": lat, "Lon": lon, "Radius": radius})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+// ForEachScheduledTrip calls fn with every trip of a route schedule as it is
+// decoded, without holding the whole response in memory. directionNum is 0
+// or 1.
+func (a *API) ForEachScheduledTrip(ctx context.Context, req ScheduleRequest, fn func(directionNum int, trip Trip) error, opts ...option.Option) error {
+	if err := req.Validate(); err != nil {
+		return err
 	}
+	return helpers.ForEach(ctx, a.requester, helpers.RouteSchedule, req.params(), func(field string, trip Trip) error {
+		if field == "Direction1" {
+			return fn(1, trip)
+		}
+		return fn(0, trip)
+	}, []string{"Direction0", "Direction1"}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
+func (a *API) GetScheduleAtStop(ctx context.Context, req ScheduleAtStopRequest, opts ...option.Option) (*ScheduleArrivalsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	var response StopsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+	return helpers.Get[ScheduleArrivalsResponse](ctx, a.requester, helpers.StopSchedule, req.params(), opts...)
+}
+
+func (a *API) GetStops(ctx context.Context, req StopsRequest, opts ...option.Option) (*StopsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	return &response, nil
+	return helpers.Get[StopsResponse](ctx, a.requester, helpers.Stops, req.params(), opts...)
 }
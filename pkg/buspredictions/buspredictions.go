@@ -2,20 +2,20 @@ package buspredictions
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type BusPrediction struct {
 	Predictions []struct {
-		DirectionNum  string `json:"DirectionNum"`
-		DirectionText string `json:"DirectionText"`
-		Minutes       int    `json:"Minutes"`
-		RouteID       string `json:"RouteID"`
-		TripID        string `json:"TripID"`
-		VehicleID     string `json:"VehicleID"`
+		DirectionNum  string      `json:"DirectionNum"`
+		DirectionText string      `json:"DirectionText"`
+		Minutes       int         `json:"Minutes"`
+		RouteID       ids.RouteID `json:"RouteID"`
+		TripID        string      `json:"TripID"`
+		VehicleID     string      `json:"VehicleID"`
 	} `json:"Predictions"`
 	StopName string `json:"StopName"`
 }
@@ -24,26 +24,21 @@ type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
 	}
 }
 
-func (a *API) GetBusPredictions(ctx context.Context, stopID string) (*BusPrediction, error) {
-	url, err := helpers.GenerateUrl("NextBusService.svc/json/jPredictions", map[string]string{"StopID": stopID})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var busPredictions BusPrediction
-	err = json.Unmarshal(responseBody, &busPredictions)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+func (a *API) GetBusPredictions(ctx context.Context, stopID ids.StopID, opts ...option.Option) (*BusPrediction, error) {
+	if err := helpers.Required("StopID", string(stopID)); err != nil {
+		return nil, err
 	}
-	return &busPredictions, nil
+	return helpers.Get[BusPrediction](ctx, a.requester, helpers.BusPredictions, map[string]string{"StopID": string(stopID)}, opts...)
 }
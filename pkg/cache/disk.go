@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskVersion is bumped whenever the on-disk entry format changes. Entries
+// written with another version are treated as misses.
+const diskVersion = 1
+
+const diskExt = ".json"
+
+// tempPrefix names the files entries are written to before being renamed.
+// Files left behind by a crashed writer are removed by Purge once they are
+// older than staleTempAge, which leaves time for other processes' writes.
+const (
+	tempPrefix   = ".tmp-"
+	staleTempAge = time.Minute
+)
+
+type diskEntry struct {
+	Version  int         `json:"version"`
+	Key      string      `json:"key"`
+	Body     []byte      `json:"body"`
+	Header   http.Header `json:"header,omitempty"`
+	StoredAt time.Time   `json:"stored_at"`
+	Expires  time.Time   `json:"expires"`
+}
+
+// Disk is a Cache storing one file per entry in a directory, so that entries
+// survive restarts. Writes are atomic, so a directory can be shared by
+// several processes.
+type Disk struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// DefaultDir returns the directory used by the wmata-cache command, under
+// the user cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserCacheDir: %w", err)
+	}
+	return filepath.Join(dir, "wmata-go"), nil
+}
+
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+	return &Disk{dir: dir}, nil
+}
+
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+diskExt)
+}
+
+func (d *Disk) Get(key string) (*Entry, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	e, err := readDiskEntry(d.path(key))
+	if err != nil || e.Version != diskVersion || e.Key != key {
+		return nil, false
+	}
+	return &Entry{
+		Body:     e.Body,
+		Header:   e.Header,
+		StoredAt: e.StoredAt,
+		Expires:  e.Expires,
+	}, true
+}
+
+// Set writes the entry. Errors are ignored since a failed write only costs a
+// cache miss; use Put to observe them.
+func (d *Disk) Set(key string, entry *Entry) {
+	_ = d.Put(key, entry)
+}
+
+// Put is Set returning any error encountered while writing the entry.
+func (d *Disk) Put(key string, entry *Entry) error {
+	b, err := json.Marshal(diskEntry{
+		Version:  diskVersion,
+		Key:      key,
+		Body:     entry.Body,
+		Header:   entry.Header,
+		StoredAt: entry.StoredAt,
+		Expires:  entry.Expires,
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tmp, err := os.CreateTemp(d.dir, tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("tmp.Write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+	return nil
+}
+
+func (d *Disk) Delete(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_ = os.Remove(d.path(key))
+}
+
+// Purge removes every entry, or only the expired and outdated ones if
+// expiredOnly is set, along with abandoned temporary files. It returns the
+// number of entries removed.
+func (d *Disk) Purge(expiredOnly bool) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return 0, fmt.Errorf("os.ReadDir: %w", err)
+	}
+	now := time.Now()
+	removed := 0
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), tempPrefix) {
+			if err := d.removeStaleTemp(f, now); err != nil {
+				return removed, err
+			}
+			continue
+		}
+		if f.IsDir() || !strings.HasSuffix(f.Name(), diskExt) {
+			continue
+		}
+		path := filepath.Join(d.dir, f.Name())
+		if expiredOnly {
+			e, err := readDiskEntry(path)
+			if err == nil && e.Version == diskVersion && now.Before(e.Expires) {
+				continue
+			}
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("os.Remove: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (d *Disk) removeStaleTemp(f os.DirEntry, now time.Time) error {
+	info, err := f.Info()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("f.Info: %w", err)
+	}
+	if now.Sub(info.ModTime()) < staleTempAge {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(d.dir, f.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+	return nil
+}
+
+func readDiskEntry(path string) (*diskEntry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+	var e diskEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	return &e, nil
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPurgeRemovesAbandonedTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	d, err := NewDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abandoned := filepath.Join(dir, tempPrefix+"abandoned")
+	inFlight := filepath.Join(dir, tempPrefix+"in-flight")
+	for _, path := range []string{abandoned, inFlight} {
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * staleTempAge)
+	if err := os.Chtimes(abandoned, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := d.Purge(true); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if _, err := os.Stat(abandoned); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("abandoned temp file not removed: %v", err)
+	}
+	if _, err := os.Stat(inFlight); err != nil {
+		t.Errorf("in-flight temp file removed: %v", err)
+	}
+}
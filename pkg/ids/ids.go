@@ -0,0 +1,67 @@
+// Package ids defines the identifiers used by the WMATA API. They are strings
+// underneath and encode to and from JSON as such.
+package ids
+
+// LineCode identifies a rail line, e.g. "RD". See the lines package for the
+// known lines.
+type LineCode string
+
+// StationCode identifies a rail station platform, e.g. "A01". See the
+// stations package for the known stations.
+type StationCode string
+
+// AllStations asks GetRailPredictions for the predictions of every station.
+// It is not a valid StationCode.
+const AllStations StationCode = "All"
+
+// RouteID identifies a bus route or route variation, e.g. "10A" or "10Av1".
+type RouteID string
+
+// StopID identifies a bus stop, e.g. "1001195".
+type StopID string
+
+func (c LineCode) String() string    { return string(c) }
+func (c StationCode) String() string { return string(c) }
+func (id RouteID) String() string    { return string(id) }
+func (id StopID) String() string     { return string(id) }
+
+// Valid reports whether the code is two upper case letters.
+func (c LineCode) Valid() bool {
+	return len(c) == 2 && isUpper(c[0]) && isUpper(c[1])
+}
+
+// Valid reports whether the code is an upper case letter followed by two
+// digits.
+func (c StationCode) Valid() bool {
+	return len(c) == 3 && isUpper(c[0]) && isDigit(c[1]) && isDigit(c[2])
+}
+
+// Valid reports whether the ID is made of letters and digits.
+func (id RouteID) Valid() bool {
+	if id == "" {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !isUpper(id[i]) && !isLower(id[i]) && !isDigit(id[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid reports whether the ID is made of digits.
+func (id StopID) Valid() bool {
+	if id == "" {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if !isDigit(id[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUpper(b byte) bool { return 'A' <= b && b <= 'Z' }
+func isLower(b byte) bool { return 'a' <= b && b <= 'z' }
+func isDigit(b byte) bool { return '0' <= b && b <= '9' }
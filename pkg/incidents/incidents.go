@@ -2,18 +2,19 @@ package incidents
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"time"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type BusIncident struct {
-	DateUpdated    string   `json:"DateUpdated"`
-	Description    string   `json:"Description"`
-	IncidentID     string   `json:"IncidentID"`
-	IncidentType   string   `json:"IncidentType"`
-	RoutesAffected []string `json:"RoutesAffected"`
+	DateUpdated    string        `json:"DateUpdated"`
+	Description    string        `json:"Description"`
+	IncidentID     string        `json:"IncidentID"`
+	IncidentType   string        `json:"IncidentType"`
+	RoutesAffected []ids.RouteID `json:"RoutesAffected"`
 }
 
 type BusIncidentResponse struct {
@@ -21,19 +22,19 @@ type BusIncidentResponse struct {
 }
 
 type ElevatorIncident struct {
-	DateOutOfServ            string `json:"DateOutOfServ"`
-	DateUpdated              string `json:"DateUpdated"`
-	DisplayOrder             int    `json:"DisplayOrder"`
-	EstimatedReturnToService string `json:"EstimatedReturnToService"`
-	LocationDescription      string `json:"LocationDescription"`
-	StationCode              string `json:"StationCode"`
-	StationName              string `json:"StationName"`
-	SymptomCode              string `json:"SymptomCode"`
-	SymptomDescription       string `json:"SymptomDescription"`
-	TimeOutOfService         string `json:"TimeOutOfService"`
-	UnitName                 string `json:"UnitName"`
-	UnitStatus               string `json:"UnitStatus"`
-	UnitType                 string `json:"UnitType"`
+	DateOutOfServ            string          `json:"DateOutOfServ"`
+	DateUpdated              string          `json:"DateUpdated"`
+	DisplayOrder             int             `json:"DisplayOrder"`
+	EstimatedReturnToService string          `json:"EstimatedReturnToService"`
+	LocationDescription      string          `json:"LocationDescription"`
+	StationCode              ids.StationCode `json:"StationCode"`
+	StationName              string          `json:"StationName"`
+	SymptomCode              string          `json:"SymptomCode"`
+	SymptomDescription       string          `json:"SymptomDescription"`
+	TimeOutOfService         string          `json:"TimeOutOfService"`
+	UnitName                 string          `json:"UnitName"`
+	UnitStatus               string          `json:"UnitStatus"`
+	UnitType                 string          `json:"UnitType"`
 }
 
 type ElevatorIncidentResponse struct {
@@ -52,66 +53,60 @@ type RailIncidentResponse struct {
 	Incidents []RailIncident `json:"Incidents"`
 }
 
+// ParsedDateUpdated returns DateUpdated in Eastern time, or the zero time if
+// it is unset.
+func (i BusIncident) ParsedDateUpdated() (time.Time, error) {
+	return helpers.ParseTimestamp(i.DateUpdated)
+}
+
+// ParsedDateOutOfServ returns DateOutOfServ in Eastern time, or the zero time
+// if it is unset.
+func (i ElevatorIncident) ParsedDateOutOfServ() (time.Time, error) {
+	return helpers.ParseTimestamp(i.DateOutOfServ)
+}
+
+// ParsedDateUpdated returns DateUpdated in Eastern time, or the zero time if
+// it is unset.
+func (i ElevatorIncident) ParsedDateUpdated() (time.Time, error) {
+	return helpers.ParseTimestamp(i.DateUpdated)
+}
+
+// ParsedEstimatedReturnToService returns EstimatedReturnToService in Eastern
+// time, or the zero time if it is unset.
+func (i ElevatorIncident) ParsedEstimatedReturnToService() (time.Time, error) {
+	return helpers.ParseTimestamp(i.EstimatedReturnToService)
+}
+
+// ParsedDateUpdated returns DateUpdated in Eastern time, or the zero time if
+// it is unset.
+func (i RailIncident) ParsedDateUpdated() (time.Time, error) {
+	return helpers.ParseTimestamp(i.DateUpdated)
+}
+
 type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
 	}
 }
 
-func (a *API) GetBusIncidents(ctx context.Context, route string) (*BusIncidentResponse, error) {
-	url, err := helpers.GenerateUrl("Incidents.svc/json/BusIncidents", map[string]string{"Route": route})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response BusIncidentResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
-	return &response, nil
 }
 
-func (a *API) GetElevatorIncidents(ctx context.Context, stationCode string) (*ElevatorIncidentResponse, error) {
-	url, err := helpers.GenerateUrl("Incidents.svc/json/ElevatorIncidents", map[string]string{"StationCode": stationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response ElevatorIncidentResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetBusIncidents(ctx context.Context, route ids.RouteID, opts ...option.Option) (*BusIncidentResponse, error) {
+	return helpers.Get[BusIncidentResponse](ctx, a.requester, helpers.BusIncidents, map[string]string{"Route": string(route)}, opts...)
 }
 
-func (a *API) GetRailIncidents(ctx context.Context) (*RailIncidentResponse, error) {
-	url, err := helpers.GenerateUrl("Incidents.svc/json/Incidents", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+func (a *API) GetElevatorIncidents(ctx context.Context, stationCode ids.StationCode, opts ...option.Option) (*ElevatorIncidentResponse, error) {
+	return helpers.Get[ElevatorIncidentResponse](ctx, a.requester, helpers.ElevatorIncidents, map[string]string{"StationCode": string(stationCode)}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response RailIncidentResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetRailIncidents(ctx context.Context, opts ...option.Option) (*RailIncidentResponse, error) {
+	return helpers.Get[RailIncidentResponse](ctx, a.requester, helpers.RailIncidents, nil, opts...)
 }
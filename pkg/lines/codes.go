@@ -0,0 +1,33 @@
+// Code generated by wmata-registry from lines.json. DO NOT EDIT.
+
+package lines
+
+import "github.com/thompsonja/wmata-go/pkg/ids"
+
+const (
+	Blue   ids.LineCode = "BL"
+	Green  ids.LineCode = "GR"
+	Orange ids.LineCode = "OR"
+	Red    ids.LineCode = "RD"
+	Silver ids.LineCode = "SV"
+	Yellow ids.LineCode = "YL"
+)
+
+// All is every line.
+var All = []ids.LineCode{
+	Blue,
+	Green,
+	Orange,
+	Red,
+	Silver,
+	Yellow,
+}
+
+var names = map[ids.LineCode]string{
+	Blue:   "Blue",
+	Green:  "Green",
+	Orange: "Orange",
+	Red:    "Red",
+	Silver: "Silver",
+	Yellow: "Yellow",
+}
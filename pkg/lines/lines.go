@@ -0,0 +1,18 @@
+// Package lines is the registry of WMATA rail lines.
+package lines
+
+import "github.com/thompsonja/wmata-go/pkg/ids"
+
+//go:generate go run ../../cmd/wmata-registry -kind lines -lines lines.json -o codes.go
+
+// Name returns the display name of a line, e.g. "Red".
+func Name(code ids.LineCode) (string, bool) {
+	name, ok := names[code]
+	return name, ok
+}
+
+// Known reports whether code is a line in the registry.
+func Known(code ids.LineCode) bool {
+	_, ok := names[code]
+	return ok
+}
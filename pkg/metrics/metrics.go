@@ -0,0 +1,150 @@
+// Package metrics exports SDK usage and latency as Prometheus metrics.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thompsonja/wmata-go/internal/helpers"
+)
+
+// Collector is a prometheus.Collector recording the calls made through the
+// clients it is passed to with option.WithObserver.
+type Collector struct {
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	cache           *prometheus.CounterVec
+	rateLimitWait   *prometheus.HistogramVec
+	budgetRemaining prometheus.Gauge
+}
+
+var (
+	_ prometheus.Collector = (*Collector)(nil)
+	_ helpers.Observer     = (*Collector)(nil)
+)
+
+type Option func(*config)
+
+type config struct {
+	namespace string
+	buckets   []float64
+}
+
+// WithNamespace sets the metric namespace, "wmata" by default.
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the latency histogram buckets, in seconds.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+func New(opts ...Option) *Collector {
+	cfg := config{
+		namespace: "wmata",
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "requests_total",
+			Help:      "Calls to the WMATA API by endpoint and HTTP status code, including cache hits.",
+		}, []string{"endpoint", "code"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "errors_total",
+			Help:      "Failed calls to the WMATA API by endpoint and error class.",
+		}, []string{"endpoint", "class"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of calls to the WMATA API, including retries and rate limiter waits.",
+			Buckets:   cfg.buckets,
+		}, []string{"endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "retries_total",
+			Help:      "Retried requests to the WMATA API by endpoint.",
+		}, []string{"endpoint"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: cfg.namespace,
+			Name:      "cache_requests_total",
+			Help:      "Calls to the WMATA API by endpoint and cache result (hit or miss).",
+		}, []string{"endpoint", "result"}),
+		rateLimitWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Name:      "rate_limiter_wait_seconds",
+			Help:      "Time spent waiting for the client-side rate limiter.",
+			Buckets:   cfg.buckets,
+		}, []string{"endpoint"}),
+		budgetRemaining: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: cfg.namespace,
+			Name:      "daily_budget_remaining",
+			Help:      "Calls left in the client-side daily budget, or -1 if there is no daily limit.",
+		}),
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.requests,
+		c.errors,
+		c.latency,
+		c.retries,
+		c.cache,
+		c.rateLimitWait,
+		c.budgetRemaining,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (c *Collector) ObserveCall(endpoint string, statusCode int, latency time.Duration, cacheHit bool, err error) {
+	code := strconv.Itoa(statusCode)
+	if statusCode == 0 {
+		code = "error"
+	}
+	c.requests.WithLabelValues(endpoint, code).Inc()
+	if err != nil {
+		c.errors.WithLabelValues(endpoint, helpers.ErrorClass(err)).Inc()
+	}
+	c.latency.WithLabelValues(endpoint).Observe(latency.Seconds())
+	if cacheHit {
+		c.cache.WithLabelValues(endpoint, "hit").Inc()
+	} else {
+		c.cache.WithLabelValues(endpoint, "miss").Inc()
+	}
+}
+
+func (c *Collector) ObserveAttempt(endpoint string, attempt int, rateLimitWait time.Duration) {
+	if attempt > 1 {
+		c.retries.WithLabelValues(endpoint).Inc()
+	}
+	c.rateLimitWait.WithLabelValues(endpoint).Observe(rateLimitWait.Seconds())
+}
+
+func (c *Collector) ObserveRemainingBudget(remaining int) {
+	c.budgetRemaining.Set(float64(remaining))
+}
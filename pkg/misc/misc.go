@@ -2,29 +2,28 @@ package misc
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
 	}
 }
 
-func (a *API) Validate(ctx context.Context) error {
-	url, err := helpers.GenerateUrl("Misc/Validate", nil)
-	if err != nil {
-		return fmt.Errorf("helpers.GenerateUrl: %v", err)
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
+}
 
-	if _, err := a.requester.SendHttpRequest(ctx, url, nil); err != nil {
-		return fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	return nil
+func (a *API) Validate(ctx context.Context, opts ...option.Option) error {
+	_, err := helpers.Send(ctx, a.requester, helpers.Validate, nil, opts...)
+	return err
 }
@@ -0,0 +1,199 @@
+// Package option contains the options shared by every WMATA API client.
+package option
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/cache"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures a WMATA API client.
+type Option = helpers.Option
+
+// WithBaseURL overrides the WMATA API base URL, e.g. to point at a local stub
+// server.
+func WithBaseURL(baseURL string) Option {
+	return helpers.WithBaseURL(baseURL)
+}
+
+// WithHTTPClient sets the *http.Client used to send requests. By default all
+// clients share one tuned transport pooling connections to WMATA.
+func WithHTTPClient(client *http.Client) Option {
+	return helpers.WithHTTPClient(client)
+}
+
+// WithTransport sets the http.RoundTripper used to send requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return helpers.WithTransport(transport)
+}
+
+// WithTimeout bounds a whole call, including retries. It defaults to 30
+// seconds, or to the timeout of the client set by WithHTTPClient.
+func WithTimeout(timeout time.Duration) Option {
+	return helpers.WithTimeout(timeout)
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return helpers.WithUserAgent(userAgent)
+}
+
+// WithHeader adds a header sent with every request.
+func WithHeader(key, value string) Option {
+	return helpers.WithHeader(key, value)
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy = helpers.RetryPolicy
+
+var (
+	// DefaultRetryPolicy is used unless another policy is configured.
+	DefaultRetryPolicy = helpers.DefaultRetryPolicy
+	// NoRetry disables retries.
+	NoRetry = helpers.NoRetry
+)
+
+// WithRetryPolicy sets the policy used to retry requests failing with a 429,
+// a 5xx or a network error.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return helpers.WithRetryPolicy(policy)
+}
+
+// RateLimit configures the client-side token bucket shared by every call.
+type RateLimit = helpers.RateLimit
+
+// DefaultRateLimit matches the WMATA default tier.
+var DefaultRateLimit = helpers.DefaultRateLimit
+
+// WithRateLimit sets the client-side per-second and per-day budgets. It has
+// no effect when passed to a single call.
+func WithRateLimit(limit RateLimit) Option {
+	return helpers.WithRateLimit(limit)
+}
+
+// WithFailFast makes calls fail with ErrRateLimitExceeded instead of waiting
+// when the client-side rate limit is reached.
+func WithFailFast(failFast bool) Option {
+	return helpers.WithFailFast(failFast)
+}
+
+// CallRecorder accounts for the calls made against the API key, e.g. a
+// *quota.Planner.
+type CallRecorder = helpers.CallRecorder
+
+// WithCallRecorder notifies recorder of every request sent to WMATA,
+// including retries.
+func WithCallRecorder(recorder CallRecorder) Option {
+	return helpers.WithCallRecorder(recorder)
+}
+
+// WithCache stores responses in c. Each endpoint is cached for its default
+// TTL, e.g. a day for static reference data and seconds for predictions.
+func WithCache(c cache.Cache) Option {
+	return helpers.WithCache(c)
+}
+
+// WithCacheTTL overrides the cache TTL of an endpoint such as
+// "Rail.svc/json/jStations" or "StationPrediction.svc/json/GetPrediction". An
+// endpoint ending in a slash matches every endpoint below it. A zero TTL
+// disables caching for the endpoint.
+func WithCacheTTL(endpoint string, ttl time.Duration) Option {
+	return helpers.WithCacheTTL(endpoint, ttl)
+}
+
+// WithoutCache bypasses the cache lookup. Pass it to a single call to force a
+// fresh response; the response is still stored in the cache.
+func WithoutCache() Option {
+	return helpers.WithoutCache()
+}
+
+// WithStaleWhileRevalidate serves expired cache entries while refreshing them
+// in the background, so that the last successful response keeps being served
+// while WMATA is down or returns errors. Entries older than maxStale are never
+// served; zero means no limit. It requires WithCache.
+func WithStaleWhileRevalidate(maxStale time.Duration) Option {
+	return helpers.WithStaleWhileRevalidate(maxStale)
+}
+
+// Middleware wraps the transport used to send every request to WMATA.
+// Middlewares run in the order they are configured, around the built-in
+// retry and rate limiting middlewares.
+type Middleware = helpers.Middleware
+
+// RoundTripperFunc adapts a function to an http.RoundTripper.
+type RoundTripperFunc = helpers.RoundTripperFunc
+
+// MetricsRecorder receives the outcome of every request.
+type MetricsRecorder = helpers.MetricsRecorder
+
+// WithMiddleware appends middlewares to the chain run around every request.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return helpers.WithMiddleware(middlewares...)
+}
+
+// RetryMiddleware retries requests according to policy. Use it with
+// WithRetryPolicy(NoRetry) to control where retries happen in the chain.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return helpers.RetryMiddleware(policy)
+}
+
+// LoggingMiddleware logs the endpoint, status and latency of every request.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return helpers.LoggingMiddleware(logger)
+}
+
+// MetricsMiddleware reports the status and latency of every request to
+// recorder.
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return helpers.MetricsMiddleware(recorder)
+}
+
+// RequestEndpoint returns the WMATA endpoint a request is sent to, e.g.
+// "Rail.svc/json/jStations", for use in middlewares.
+func RequestEndpoint(req *http.Request) string {
+	return helpers.RequestEndpoint(req)
+}
+
+// WithLogger logs every call to logger: method, endpoint, query parameters,
+// status, latency, size and cache hits. Response bodies and request headers
+// are logged at debug level. The API key is always redacted.
+func WithLogger(logger *slog.Logger) Option {
+	return helpers.WithLogger(logger)
+}
+
+// WithTracerProvider records a span for every call, named after the WMATA
+// endpoint. It defaults to the global tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return helpers.WithTracerProvider(tp)
+}
+
+// WithPropagator sets how the trace context is propagated to WMATA. It
+// defaults to the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return helpers.WithPropagator(propagator)
+}
+
+// Observer receives the events needed to export SDK usage metrics, e.g. a
+// *metrics.Collector.
+type Observer = helpers.Observer
+
+// WithObserver reports every call, attempt and rate limiter wait to observer.
+func WithObserver(observer Observer) Option {
+	return helpers.WithObserver(observer)
+}
+
+// ResponseMeta describes the response to a call: its raw body, headers,
+// status, URL (which never contains the API key), fetch time and latency.
+type ResponseMeta = helpers.Result
+
+// WithResponseMeta stores the metadata of the response in dst when the call
+// succeeds. It is meant to be passed to a single call.
+func WithResponseMeta(dst *ResponseMeta) Option {
+	return helpers.WithResponseMeta(dst)
+}
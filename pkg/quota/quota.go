@@ -0,0 +1,290 @@
+// Package quota tracks calls against the daily WMATA key budget and stretches
+// polling intervals so that the key never runs out before midnight Eastern.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+)
+
+const dayLayout = "2006-01-02"
+
+// saveDelay is how long call counts are batched before the state file is
+// written.
+const saveDelay = time.Second
+
+type Planner struct {
+	budget    int
+	reserve   int
+	stateFile string
+	now       func() time.Time
+
+	mu      sync.Mutex
+	day     time.Time
+	counts  map[string]int
+	pollers []*Poller
+	// saveTimer is set while a save of the state file is pending.
+	saveTimer *time.Timer
+
+	// saveMu serializes writes of the state file.
+	saveMu sync.Mutex
+}
+
+type Option func(*Planner)
+
+// WithStateFile persists the call counters to path so that they survive
+// restarts. The file is written at most once a second, and by Close.
+func WithStateFile(path string) Option {
+	return func(p *Planner) {
+		p.stateFile = path
+	}
+}
+
+// WithClock makes the planner read the time from now rather than time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(p *Planner) {
+		p.now = now
+	}
+}
+
+// WithReserve keeps n calls of the daily budget out of the pollers' reach,
+// for ad hoc calls.
+func WithReserve(n int) Option {
+	return func(p *Planner) {
+		p.reserve = n
+	}
+}
+
+type state struct {
+	Day    string         `json:"day"`
+	Counts map[string]int `json:"counts"`
+}
+
+// New returns a Planner for a key allowing dailyBudget calls per day. Pass it
+// to option.WithCallRecorder so that every call is accounted for.
+func New(dailyBudget int, opts ...Option) (*Planner, error) {
+	p := &Planner{
+		budget: dailyBudget,
+		now:    time.Now,
+		counts: map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.day = helpers.ServiceDay(p.now())
+
+	if p.stateFile != "" {
+		if err := p.load(); err != nil {
+			return nil, fmt.Errorf("p.load: %w", err)
+		}
+	}
+	return p, nil
+}
+
+func (p *Planner) load() error {
+	b, err := os.ReadFile(p.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if s.Day == p.day.Format(dayLayout) && s.Counts != nil {
+		p.counts = s.Counts
+	}
+	return nil
+}
+
+// flush writes the counters if a save is pending.
+func (p *Planner) flush() error {
+	p.saveMu.Lock()
+	defer p.saveMu.Unlock()
+
+	p.mu.Lock()
+	if p.saveTimer == nil {
+		p.mu.Unlock()
+		return nil
+	}
+	p.saveTimer.Stop()
+	p.saveTimer = nil
+	s := state{Day: p.day.Format(dayLayout), Counts: make(map[string]int, len(p.counts))}
+	for k, v := range p.counts {
+		s.Counts[k] = v
+	}
+	p.mu.Unlock()
+
+	return p.save(s)
+}
+
+// Close writes any pending counters to the state file.
+func (p *Planner) Close() error {
+	return p.flush()
+}
+
+// save writes the counters atomically.
+func (p *Planner) save(s state) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.stateFile), filepath.Base(p.stateFile)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("tmp.Write: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.stateFile); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+	return nil
+}
+
+// resetDay clears the counters when a new Eastern day starts. p.mu must be
+// held.
+func (p *Planner) resetDay(now time.Time) {
+	if day := helpers.ServiceDay(now); !day.Equal(p.day) {
+		p.day = day
+		p.counts = map[string]int{}
+	}
+}
+
+// RecordCall accounts for one call to endpoint. Persistence errors are
+// ignored; the in-memory counters stay authoritative.
+func (p *Planner) RecordCall(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetDay(p.now())
+	p.counts[endpoint]++
+	if p.stateFile != "" && p.saveTimer == nil {
+		p.saveTimer = time.AfterFunc(saveDelay, func() {
+			_ = p.flush()
+		})
+	}
+}
+
+// Counts returns today's number of calls per endpoint.
+func (p *Planner) Counts() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetDay(p.now())
+	counts := make(map[string]int, len(p.counts))
+	for k, v := range p.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// Remaining returns the number of calls left in today's budget.
+func (p *Planner) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resetDay(p.now())
+	return p.remaining()
+}
+
+func (p *Planner) remaining() int {
+	used := 0
+	for _, n := range p.counts {
+		used += n
+	}
+	return p.budget - used
+}
+
+// Poller is a periodic caller whose interval is stretched by its Planner when
+// the daily budget would otherwise run out.
+type Poller struct {
+	Name     string
+	Interval time.Duration
+
+	planner *Planner
+}
+
+// Register adds a poller that wants to make one call every interval, which
+// must be positive.
+func (p *Planner) Register(name string, interval time.Duration) (*Poller, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("poller %s: interval %v is not positive", name, interval)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	poller := &Poller{Name: name, Interval: interval, planner: p}
+	p.pollers = append(p.pollers, poller)
+	return poller, nil
+}
+
+// Unregister removes a poller, giving its share of the budget back to the
+// others.
+func (p *Planner) Unregister(poller *Poller) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, other := range p.pollers {
+		if other == poller {
+			p.pollers = append(p.pollers[:i], p.pollers[i+1:]...)
+			return
+		}
+	}
+}
+
+// stretch returns the factor by which every poller interval must be
+// multiplied so that the pollers fit in the available budget until midnight.
+// p.mu must be held.
+func (p *Planner) stretch(untilMidnight time.Duration, available int) float64 {
+	wanted := 0.0
+	for _, poller := range p.pollers {
+		wanted += untilMidnight.Seconds() / poller.Interval.Seconds()
+	}
+	if wanted <= float64(available) {
+		return 1
+	}
+	return wanted / float64(available)
+}
+
+// NextInterval returns how long the poller should wait before its next call.
+func (poller *Poller) NextInterval() time.Duration {
+	p := poller.planner
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	p.resetDay(now)
+
+	untilMidnight := p.day.AddDate(0, 0, 1).Sub(now)
+	available := p.remaining() - p.reserve
+	if available <= 0 {
+		// Out of budget: wait for the counters to reset.
+		return untilMidnight
+	}
+	return time.Duration(float64(poller.Interval) * p.stretch(untilMidnight, available))
+}
+
+// Run calls fn immediately and then after every NextInterval until ctx is
+// done.
+func (poller *Poller) Run(ctx context.Context, fn func(context.Context)) error {
+	for {
+		fn(ctx)
+		timer := time.NewTimer(poller.NextInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
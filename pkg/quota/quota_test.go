@@ -0,0 +1,177 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+)
+
+// clock is a settable time source.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func eastern(day, hour, minute int) time.Time {
+	return time.Date(2024, time.May, day, hour, minute, 0, 0, helpers.Eastern)
+}
+
+func newPlanner(t *testing.T, budget int, c *clock, opts ...Option) *Planner {
+	t.Helper()
+	p, err := New(budget, append([]Option{WithClock(c.now)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func register(t *testing.T, p *Planner, name string, interval time.Duration) *Poller {
+	t.Helper()
+	poller, err := p.Register(name, interval)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return poller
+}
+
+func TestRegisterRejectsNonPositiveIntervals(t *testing.T) {
+	p := newPlanner(t, 100, &clock{eastern(1, 12, 0)})
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := p.Register("poller", interval); err == nil {
+			t.Errorf("Register(%v) succeeded, want error", interval)
+		}
+	}
+}
+
+func TestNextInterval(t *testing.T) {
+	// At noon, a 10s poller wants 4320 calls before midnight and a 20s one
+	// 2160.
+	for _, tc := range []struct {
+		name      string
+		budget    int
+		reserve   int
+		used      int
+		want10s   time.Duration
+		wantOther time.Duration
+	}{
+		{name: "within budget", budget: 10000, want10s: 10 * time.Second, wantOther: 20 * time.Second},
+		{name: "exact budget", budget: 6480, want10s: 10 * time.Second, wantOther: 20 * time.Second},
+		{name: "half budget", budget: 3240, want10s: 20 * time.Second, wantOther: 40 * time.Second},
+		{name: "reserve", budget: 4320, reserve: 2160, want10s: 30 * time.Second, wantOther: time.Minute},
+		{name: "calls made", budget: 5400, used: 3240, want10s: 30 * time.Second, wantOther: time.Minute},
+		{name: "exhausted", budget: 100, used: 100, want10s: 12 * time.Hour, wantOther: 12 * time.Hour},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPlanner(t, tc.budget, &clock{eastern(1, 12, 0)}, WithReserve(tc.reserve))
+			fast := register(t, p, "fast", 10*time.Second)
+			slow := register(t, p, "slow", 20*time.Second)
+			for i := 0; i < tc.used; i++ {
+				p.RecordCall("endpoint")
+			}
+			if got := fast.NextInterval(); got != tc.want10s {
+				t.Errorf("fast: NextInterval = %v, want %v", got, tc.want10s)
+			}
+			if got := slow.NextInterval(); got != tc.wantOther {
+				t.Errorf("slow: NextInterval = %v, want %v", got, tc.wantOther)
+			}
+		})
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	p := newPlanner(t, 3240, &clock{eastern(1, 12, 0)})
+	fast := register(t, p, "fast", 10*time.Second)
+	slow := register(t, p, "slow", 20*time.Second)
+	if got, want := fast.NextInterval(), 20*time.Second; got != want {
+		t.Errorf("NextInterval = %v, want %v", got, want)
+	}
+	p.Unregister(slow)
+	if got, want := fast.NextInterval(), 40*time.Second/3; got != want {
+		t.Errorf("after Unregister: NextInterval = %v, want %v", got, want)
+	}
+}
+
+func TestResetAtMidnightEastern(t *testing.T) {
+	c := &clock{eastern(1, 19, 59)}
+	p := newPlanner(t, 100, c)
+	p.RecordCall("a")
+	p.RecordCall("a")
+
+	// Midnight UTC is 8pm Eastern in May.
+	c.t = eastern(1, 20, 1)
+	if got := p.Remaining(); got != 98 {
+		t.Errorf("after midnight UTC: Remaining = %d, want 98", got)
+	}
+
+	c.t = eastern(1, 23, 59)
+	p.RecordCall("b")
+	if got := p.Counts(); got["a"] != 2 || got["b"] != 1 {
+		t.Errorf("Counts = %v, want a: 2, b: 1", got)
+	}
+
+	c.t = eastern(2, 0, 0)
+	if got := p.Counts(); len(got) != 0 {
+		t.Errorf("after midnight Eastern: Counts = %v, want none", got)
+	}
+	if got := p.Remaining(); got != 100 {
+		t.Errorf("after midnight Eastern: Remaining = %d, want 100", got)
+	}
+}
+
+func TestStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	c := &clock{eastern(1, 12, 0)}
+
+	p, err := New(100, WithClock(c.now), WithStateFile(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	p.RecordCall("a")
+	p.RecordCall("a")
+	p.RecordCall("b")
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The counters survive a restart on the same day.
+	c.t = eastern(1, 23, 0)
+	p, err = New(100, WithClock(c.now), WithStateFile(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := p.Counts(); got["a"] != 2 || got["b"] != 1 || len(got) != 2 {
+		t.Errorf("after restart: Counts = %v, want a: 2, b: 1", got)
+	}
+	if got := p.Remaining(); got != 97 {
+		t.Errorf("after restart: Remaining = %d, want 97", got)
+	}
+
+	// But not into the next day.
+	c.t = eastern(2, 0, 30)
+	p, err = New(100, WithClock(c.now), WithStateFile(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := p.Counts(); len(got) != 0 {
+		t.Errorf("next day: Counts = %v, want none", got)
+	}
+
+	// Temporary files are cleaned up.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files in the state directory, want 1", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(100, WithClock(c.now), WithStateFile(path)); err == nil {
+		t.Error("New with a corrupt state file succeeded, want error")
+	}
+}
@@ -0,0 +1,158 @@
+package railpredictions
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thompsonja/wmata-go/pkg/ids"
+)
+
+type ArrivalKind int
+
+const (
+	// ArrivalUnknown is sent as "---", an empty string or anything else that
+	// is not understood.
+	ArrivalUnknown ArrivalKind = iota
+	ArrivalMinutes
+	ArrivalArriving
+	ArrivalBoarding
+)
+
+// Arrival is the Min field of a prediction.
+type Arrival struct {
+	Kind ArrivalKind
+	// Minutes is set when Kind is ArrivalMinutes.
+	Minutes int
+}
+
+// ParseArrival parses a Min field: a number of minutes, "ARR" or "BRD".
+func ParseArrival(min string) Arrival {
+	switch min = strings.TrimSpace(min); min {
+	case "ARR":
+		return Arrival{Kind: ArrivalArriving}
+	case "BRD":
+		return Arrival{Kind: ArrivalBoarding}
+	}
+	minutes, err := strconv.Atoi(min)
+	if err != nil || minutes < 0 {
+		return Arrival{Kind: ArrivalUnknown}
+	}
+	return Arrival{Kind: ArrivalMinutes, Minutes: minutes}
+}
+
+// String returns the arrival as WMATA sends it.
+func (a Arrival) String() string {
+	switch a.Kind {
+	case ArrivalMinutes:
+		return strconv.Itoa(a.Minutes)
+	case ArrivalArriving:
+		return "ARR"
+	case ArrivalBoarding:
+		return "BRD"
+	default:
+		return "---"
+	}
+}
+
+// rank orders boarding trains first, then arriving trains, then trains by
+// minutes, with unknown arrivals last.
+func (a Arrival) rank() int {
+	switch a.Kind {
+	case ArrivalBoarding:
+		return -2
+	case ArrivalArriving:
+		return -1
+	case ArrivalMinutes:
+		return a.Minutes
+	default:
+		return math.MaxInt
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is sooner than, as soon
+// as or later than b.
+func (a Arrival) Compare(b Arrival) int {
+	ra, rb := a.rank(), b.rank()
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (a Arrival) Before(b Arrival) bool {
+	return a.Compare(b) < 0
+}
+
+func (t Train) Arrival() Arrival {
+	return ParseArrival(t.Min)
+}
+
+// SortByArrival sorts trains from the soonest to the latest, keeping the
+// order of trains arriving at the same time.
+func SortByArrival(trains []Train) {
+	slices.SortStableFunc(trains, func(a, b Train) int {
+		return a.Arrival().Compare(b.Arrival())
+	})
+}
+
+// nonRevenueNames are the destination names WMATA sends for trains that do
+// not carry passengers, including truncated ones.
+var nonRevenueNames = map[string]bool{
+	"No Passenger":  true,
+	"No Passengers": true,
+	"NoPssenger":    true,
+	"ssenger":       true,
+}
+
+// placeholderNames are sent when the destination is not known. Such trains
+// may still carry passengers.
+var placeholderNames = map[string]bool{
+	"Train": true,
+	"No":    true,
+	"--":    true,
+}
+
+// StationResolver returns the name of a station from its code.
+type StationResolver func(code ids.StationCode) (name string, ok bool)
+
+// Destination is the destination of a train, cleaned of WMATA placeholders.
+type Destination struct {
+	Code ids.StationCode
+	// Name is empty when the destination is not known.
+	Name string
+	// NonRevenue is set for trains that do not carry passengers.
+	NonRevenue bool
+}
+
+// NormalizedDestination returns the destination of the train. The name is
+// looked up with resolve, which may be nil, and otherwise taken from the
+// prediction unless it is a placeholder.
+func (t Train) NormalizedDestination(resolve StationResolver) Destination {
+	name := strings.TrimSpace(t.DestinationName)
+	if name == "" {
+		name = strings.TrimSpace(t.Destination)
+	}
+	d := Destination{
+		Code:       t.DestinationCode,
+		NonRevenue: nonRevenueNames[name] || t.Line == "No",
+	}
+	if resolved, ok := resolveStation(resolve, t.DestinationCode); ok {
+		d.Name = resolved
+	} else if !nonRevenueNames[name] && !placeholderNames[name] {
+		d.Name = name
+	}
+	return d
+}
+
+func resolveStation(resolve StationResolver, code ids.StationCode) (string, bool) {
+	if resolve == nil || code == "" {
+		return "", false
+	}
+	return resolve(code)
+}
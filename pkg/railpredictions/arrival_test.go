@@ -0,0 +1,153 @@
+package railpredictions
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/thompsonja/wmata-go/pkg/ids"
+)
+
+func TestParseArrival(t *testing.T) {
+	for _, tc := range []struct {
+		min  string
+		want Arrival
+		str  string
+	}{
+		{min: "5", want: Arrival{Kind: ArrivalMinutes, Minutes: 5}, str: "5"},
+		{min: " 12 ", want: Arrival{Kind: ArrivalMinutes, Minutes: 12}, str: "12"},
+		{min: "0", want: Arrival{Kind: ArrivalMinutes}, str: "0"},
+		{min: "ARR", want: Arrival{Kind: ArrivalArriving}, str: "ARR"},
+		{min: "BRD", want: Arrival{Kind: ArrivalBoarding}, str: "BRD"},
+		{min: "---", want: Arrival{Kind: ArrivalUnknown}, str: "---"},
+		{min: "", want: Arrival{Kind: ArrivalUnknown}, str: "---"},
+		{min: "-1", want: Arrival{Kind: ArrivalUnknown}, str: "---"},
+		{min: "arr", want: Arrival{Kind: ArrivalUnknown}, str: "---"},
+	} {
+		got := ParseArrival(tc.min)
+		if got != tc.want {
+			t.Errorf("ParseArrival(%q) = %+v, want %+v", tc.min, got, tc.want)
+		}
+		if got.String() != tc.str {
+			t.Errorf("ParseArrival(%q).String() = %q, want %q", tc.min, got.String(), tc.str)
+		}
+	}
+}
+
+func TestArrivalCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{a: "BRD", b: "ARR", want: -1},
+		{a: "ARR", b: "0", want: -1},
+		{a: "1", b: "10", want: -1},
+		{a: "10", b: "---", want: -1},
+		{a: "3", b: "3", want: 0},
+		{a: "---", b: "", want: 0},
+		{a: "", b: "BRD", want: 1},
+	} {
+		a, b := ParseArrival(tc.a), ParseArrival(tc.b)
+		if got := a.Compare(b); got != tc.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := b.Compare(a); got != -tc.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tc.b, tc.a, got, -tc.want)
+		}
+		if got := a.Before(b); got != (tc.want < 0) {
+			t.Errorf("Before(%q, %q) = %v", tc.a, tc.b, got)
+		}
+	}
+}
+
+func TestSortByArrival(t *testing.T) {
+	trains := []Train{
+		{Min: "---", Car: "a"},
+		{Min: "7", Car: "b"},
+		{Min: "ARR", Car: "c"},
+		{Min: "", Car: "d"},
+		{Min: "BRD", Car: "e"},
+		{Min: "2", Car: "f"},
+		{Min: "7", Car: "g"},
+	}
+	SortByArrival(trains)
+	var got []string
+	for _, train := range trains {
+		got = append(got, train.Car)
+	}
+	// Trains arriving at the same time keep their order.
+	if want := []string{"e", "c", "f", "b", "g", "a", "d"}; !slices.Equal(got, want) {
+		t.Errorf("got order %v, want %v", got, want)
+	}
+}
+
+func TestNormalizedDestination(t *testing.T) {
+	resolve := func(code ids.StationCode) (string, bool) {
+		if code == "A15" {
+			return "Shady Grove", true
+		}
+		return "", false
+	}
+	for _, tc := range []struct {
+		name    string
+		train   Train
+		resolve StationResolver
+		want    Destination
+	}{
+		{
+			name:    "resolved",
+			train:   Train{DestinationCode: "A15", DestinationName: "Shady Gr", Line: "RD"},
+			resolve: resolve,
+			want:    Destination{Code: "A15", Name: "Shady Grove"},
+		},
+		{
+			name:  "no resolver",
+			train: Train{DestinationCode: "A15", DestinationName: "Shady Gr", Line: "RD"},
+			want:  Destination{Code: "A15", Name: "Shady Gr"},
+		},
+		{
+			name:    "unresolved code",
+			train:   Train{DestinationCode: "G05", DestinationName: "Largo", Line: "BL"},
+			resolve: resolve,
+			want:    Destination{Code: "G05", Name: "Largo"},
+		},
+		{
+			name:  "falls back to Destination",
+			train: Train{Destination: "Glenmont", Line: "RD"},
+			want:  Destination{Name: "Glenmont"},
+		},
+		{
+			name:  "no passenger",
+			train: Train{DestinationName: "No Passenger", Line: "--"},
+			want:  Destination{NonRevenue: true},
+		},
+		{
+			name:  "truncated no passenger",
+			train: Train{DestinationName: "ssenger", Line: "--"},
+			want:  Destination{NonRevenue: true},
+		},
+		{
+			name:  "no line",
+			train: Train{DestinationName: "No", Line: "No"},
+			want:  Destination{NonRevenue: true},
+		},
+		{
+			name:  "Train placeholder",
+			train: Train{DestinationName: "Train", Line: "--"},
+			want:  Destination{},
+		},
+		{
+			name:  "No placeholder",
+			train: Train{DestinationName: "No", Line: "RD"},
+			want:  Destination{},
+		},
+		{
+			name:  "dashes",
+			train: Train{DestinationName: "--", Line: "--"},
+			want:  Destination{},
+		},
+	} {
+		if got := tc.train.NormalizedDestination(tc.resolve); got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
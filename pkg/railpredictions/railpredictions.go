@@ -2,52 +2,60 @@ package railpredictions
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"time"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type Train struct {
-	Car             string `json:"Car"`
-	Destination     string `json:"Destination"`
-	DestinationCode string `json:"DestinationCode"`
-	DestinationName string `json:"DestinationName"`
-	Group           string `json:"Group"`
-	Line            string `json:"Line"`
-	LocationCode    string `json:"LocationCode"`
-	LocationName    string `json:"LocationName"`
-	Min             string `json:"Min"`
+	Car             string          `json:"Car"`
+	Destination     string          `json:"Destination"`
+	DestinationCode ids.StationCode `json:"DestinationCode"`
+	DestinationName string          `json:"DestinationName"`
+	Group           string          `json:"Group"`
+	Line            ids.LineCode    `json:"Line"`
+	LocationCode    ids.StationCode `json:"LocationCode"`
+	LocationName    string          `json:"LocationName"`
+	Min             string          `json:"Min"`
 }
 
 type RailPredictions struct {
 	Trains []Train `json:"Trains"`
+	// Stale and Age are those of option.ResponseMeta.
+	Stale bool          `json:"-"`
+	Age   time.Duration `json:"-"`
 }
 
 type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
 	}
 }
 
-func (a *API) GetRailPredictions(ctx context.Context, stationCode string) (*RailPredictions, error) {
-	url, err := helpers.GenerateUrl("StationPrediction.svc/json/GetPrediction/"+stationCode, nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
+// GetRailPredictions returns the predictions at a station, or at every station
+// for ids.AllStations. Several stations can be passed comma-separated, e.g.
+// "A01,C01".
+func (a *API) GetRailPredictions(ctx context.Context, stationCode ids.StationCode, opts ...option.Option) (*RailPredictions, error) {
+	if err := helpers.Required("StationCode", string(stationCode)); err != nil {
+		return nil, err
 	}
-	var railPredictions RailPredictions
-	err = json.Unmarshal(responseBody, &railPredictions)
+	railPredictions, res, err := helpers.GetResult[RailPredictions](ctx, a.requester, helpers.RailPredictions, map[string]string{"StationCode": string(stationCode)}, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+		return nil, err
 	}
-	return &railPredictions, nil
+	railPredictions.Stale = res.Stale
+	railPredictions.Age = res.Age
+	return railPredictions, nil
 }
@@ -2,29 +2,36 @@ package railstationinfo
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
+	}
+}
+
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
 }
 
 type Line struct {
-	DisplayName          string `json:"DisplayName"`
-	EndStationCode       string `json:"EndStationCode"`
-	InternalDestination1 string `json:"InternalDestination1"`
-	InternalDestination2 string `json:"InternalDestination2"`
-	LineCode             string `json:"LineCode"`
-	StartStationCode     string `json:"StartStationCode"`
+	DisplayName          string          `json:"DisplayName"`
+	EndStationCode       ids.StationCode `json:"EndStationCode"`
+	InternalDestination1 ids.StationCode `json:"InternalDestination1"`
+	InternalDestination2 ids.StationCode `json:"InternalDestination2"`
+	LineCode             ids.LineCode    `json:"LineCode"`
+	StartStationCode     ids.StationCode `json:"StartStationCode"`
 }
 
 type LinesResponse struct {
@@ -36,7 +43,7 @@ type StationsParkingResponse struct {
 }
 
 type StationParking struct {
-	Code             string           `json:"Code"`
+	Code             ids.StationCode  `json:"Code"`
 	Notes            string           `json:"Notes"`
 	AllDayParking    AllDayParking    `json:"AllDayParking"`
 	ShortTermParking ShortTermParking `json:"ShortTermParking"`
@@ -56,25 +63,80 @@ type ShortTermParking struct {
 }
 
 type MetroPathItem struct {
-	DistanceToPrev int    `json:"DistanceToPrev"`
-	LineCode       string `json:"LineCode"`
-	SeqNum         int    `json:"SeqNum"`
-	StationCode    string `json:"StationCode"`
-	StationName    string `json:"StationName"`
+	DistanceToPrev int             `json:"DistanceToPrev"`
+	LineCode       ids.LineCode    `json:"LineCode"`
+	SeqNum         int             `json:"SeqNum"`
+	StationCode    ids.StationCode `json:"StationCode"`
+	StationName    string          `json:"StationName"`
 }
 
 type PathResponse struct {
-	Path MetroPathItem `json:"Path"`
+	Path []MetroPathItem `json:"Path"`
+}
+
+const feetPerMile = 5280
+
+// TotalDistanceFeet returns the length of the path in feet.
+func (p *PathResponse) TotalDistanceFeet() int {
+	total := 0
+	for _, item := range p.Path {
+		total += item.DistanceToPrev
+	}
+	return total
+}
+
+// TotalDistanceMiles returns the length of the path in miles.
+func (p *PathResponse) TotalDistanceMiles() float64 {
+	return float64(p.TotalDistanceFeet()) / feetPerMile
+}
+
+// StationCodes returns the codes of the stations along the path, in order.
+func (p *PathResponse) StationCodes() []ids.StationCode {
+	codes := make([]ids.StationCode, len(p.Path))
+	for i, item := range p.Path {
+		codes[i] = item.StationCode
+	}
+	return codes
+}
+
+// LineCode returns the line the path runs on, or "" if the path is empty.
+func (p *PathResponse) LineCode() ids.LineCode {
+	if len(p.Path) == 0 {
+		return ""
+	}
+	return p.Path[0].LineCode
+}
+
+// Between returns the part of the path from one of its stations to a later
+// one, both included. The first station's DistanceToPrev is zeroed so that
+// the distance totals are those of the sub-path. ok is false if either
+// station is not on the path or to comes before from.
+func (p *PathResponse) Between(fromStationCode, toStationCode ids.StationCode) (path *PathResponse, ok bool) {
+	from, to := -1, -1
+	for i, item := range p.Path {
+		if item.StationCode == fromStationCode && from < 0 {
+			from = i
+		}
+		if item.StationCode == toStationCode {
+			to = i
+		}
+	}
+	if from < 0 || to < from {
+		return nil, false
+	}
+	items := append([]MetroPathItem(nil), p.Path[from:to+1]...)
+	items[0].DistanceToPrev = 0
+	return &PathResponse{Path: items}, true
 }
 
 type Entrance struct {
-	Description  string  `json:"Description"`
-	ID           string  `json:"ID"`
-	Lat          float64 `json:"Lat"`
-	Lon          float64 `json:"Lon"`
-	Name         string  `json:"Name"`
-	StationCode1 string  `json:"StationCode1"`
-	StationCode2 string  `json:"StationCode2"`
+	Description  string          `json:"Description"`
+	ID           string          `json:"ID"`
+	Lat          float64         `json:"Lat"`
+	Lon          float64         `json:"Lon"`
+	Name         string          `json:"Name"`
+	StationCode1 ids.StationCode `json:"StationCode1"`
+	StationCode2 ids.StationCode `json:"StationCode2"`
 }
 
 type EntrancesResponse struct {
@@ -82,17 +144,17 @@ type EntrancesResponse struct {
 }
 
 type Station struct {
-	Address          Address `json:"Address"`
-	Code             string  `json:"Code"`
-	Lat              float64 `json:"Lat"`
-	LineCode1        string  `json:"LineCode1"`
-	LineCode2        *string `json:"LineCode2"`
-	LineCode3        *string `json:"LineCode3"`
-	LineCode4        *string `json:"LineCode4"`
-	Lon              float64 `json:"Lon"`
-	Name             string  `json:"Name"`
-	StationTogether1 string  `json:"StationTogether1"`
-	StationTogether2 string  `json:"StationTogether2"`
+	Address          Address         `json:"Address"`
+	Code             ids.StationCode `json:"Code"`
+	Lat              float64         `json:"Lat"`
+	LineCode1        ids.LineCode    `json:"LineCode1"`
+	LineCode2        *ids.LineCode   `json:"LineCode2"`
+	LineCode3        *ids.LineCode   `json:"LineCode3"`
+	LineCode4        *ids.LineCode   `json:"LineCode4"`
+	Lon              float64         `json:"Lon"`
+	Name             string          `json:"Name"`
+	StationTogether1 ids.StationCode `json:"StationTogether1"`
+	StationTogether2 ids.StationCode `json:"StationTogether2"`
 }
 
 type Address struct {
@@ -110,30 +172,12 @@ type StationTimesResponse struct {
 	StationTimes []StationTime `json:"StationTimes"`
 }
 
-type StationTime struct {
-	Code        string      `json:"Code"`
-	StationName string      `json:"StationName"`
-	Monday      DaySchedule `json:"Monday"`
-	// Add other days of the week here
-}
-
-type DaySchedule struct {
-	OpeningTime string  `json:"OpeningTime"`
-	FirstTrains []Train `json:"FirstTrains"`
-	LastTrains  []Train `json:"LastTrains"`
-}
-
-type Train struct {
-	Time               string `json:"Time"`
-	DestinationStation string `json:"DestinationStation"`
-}
-
 type StationToStationInfo struct {
-	CompositeMiles     float64  `json:"CompositeMiles"`
-	DestinationStation string   `json:"DestinationStation"`
-	RailFare           RailFare `json:"RailFare"`
-	RailTime           int      `json:"RailTime"`
-	SourceStation      string   `json:"SourceStation"`
+	CompositeMiles     float64         `json:"CompositeMiles"`
+	DestinationStation ids.StationCode `json:"DestinationStation"`
+	RailFare           RailFare        `json:"RailFare"`
+	RailTime           int             `json:"RailTime"`
+	SourceStation      ids.StationCode `json:"SourceStation"`
 }
 
 type RailFare struct {
@@ -146,146 +190,58 @@ type StationToStationResponse struct {
 	StationToStationInfos []StationToStationInfo `json:"StationToStationInfos"`
 }
 
-func (a *API) GetLines(ctx context.Context) (*LinesResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jLines", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response LinesResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+type StationEntrancesRequest struct {
+	// Lat, Lon and Radius, in meters, restrict the entrances to an area.
+	Lat    float64
+	Lon    float64
+	Radius int
 }
 
-func (a *API) GetParkingInfo(ctx context.Context, stationCode string) (*StationsParkingResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jStationParking", map[string]string{"StationCode": stationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response StationsParkingResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (r StationEntrancesRequest) Validate() error {
+	return helpers.ValidateLocation(r.Lat, r.Lon, r.Radius)
 }
 
-func (a *API) GetPathBetweenStations(ctx context.Context, fromStationCode, toStationCode string) (*PathResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jPath", map[string]string{"FromStationCode": fromStationCode, "ToStationCode": toStationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response PathResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (r StationEntrancesRequest) params() helpers.Params {
+	return helpers.Params{}.Location(r.Lat, r.Lon, r.Radius)
 }
 
-func (a *API) GetStationEntrances(ctx context.Context, lat, lon, radius string) (*EntrancesResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jStationEntrances", map[string]string{"Lat": lat, "Lon": lon, "Radius": radius})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response EntrancesResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetLines(ctx context.Context, opts ...option.Option) (*LinesResponse, error) {
+	return helpers.Get[LinesResponse](ctx, a.requester, helpers.Lines, nil, opts...)
 }
 
-func (a *API) GetStationInfo(ctx context.Context, stationCode string) (*Station, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jStationInfo", map[string]string{"StationCode": stationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
-
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response Station
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetParkingInfo(ctx context.Context, stationCode ids.StationCode, opts ...option.Option) (*StationsParkingResponse, error) {
+	return helpers.Get[StationsParkingResponse](ctx, a.requester, helpers.StationParking, map[string]string{"StationCode": string(stationCode)}, opts...)
 }
 
-func (a *API) GetStations(ctx context.Context, lineCode string) (*StationsResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jStations", map[string]string{"LineCode": lineCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func (a *API) GetPathBetweenStations(ctx context.Context, fromStationCode, toStationCode ids.StationCode, opts ...option.Option) (*PathResponse, error) {
+	if err := errors.Join(helpers.Required("FromStationCode", string(fromStationCode)), helpers.Required("ToStationCode", string(toStationCode))); err != nil {
+		return nil, err
 	}
+	return helpers.Get[PathResponse](ctx, a.requester, helpers.Path, map[string]string{"FromStationCode": string(fromStationCode), "ToStationCode": string(toStationCode)}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response StationsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+func (a *API) GetStationEntrances(ctx context.Context, req StationEntrancesRequest, opts ...option.Option) (*EntrancesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
-	return &response, nil
+	return helpers.Get[EntrancesResponse](ctx, a.requester, helpers.StationEntrances, req.params(), opts...)
 }
 
-func (a *API) GetStationTimings(ctx context.Context, stationCode string) (*StationTimesResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jStationTimes", map[string]string{"StationCode": stationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func (a *API) GetStationInfo(ctx context.Context, stationCode ids.StationCode, opts ...option.Option) (*Station, error) {
+	if err := helpers.Required("StationCode", string(stationCode)); err != nil {
+		return nil, err
 	}
+	return helpers.Get[Station](ctx, a.requester, helpers.StationInfo, map[string]string{"StationCode": string(stationCode)}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response StationTimesResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetStations(ctx context.Context, lineCode ids.LineCode, opts ...option.Option) (*StationsResponse, error) {
+	return helpers.Get[StationsResponse](ctx, a.requester, helpers.Stations, map[string]string{"LineCode": string(lineCode)}, opts...)
 }
 
-func (a *API) GetStationToStationInfo(ctx context.Context, fromStationCode, toStationCode string) (*StationToStationResponse, error) {
-	url, err := helpers.GenerateUrl("Rail.svc/json/jSrcStationToDstStationInfo", map[string]string{"FromStationCode": fromStationCode, "ToStationCode": toStationCode})
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+func (a *API) GetStationTimings(ctx context.Context, stationCode ids.StationCode, opts ...option.Option) (*StationTimesResponse, error) {
+	return helpers.Get[StationTimesResponse](ctx, a.requester, helpers.StationTimes, map[string]string{"StationCode": string(stationCode)}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response StationToStationResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetStationToStationInfo(ctx context.Context, fromStationCode, toStationCode ids.StationCode, opts ...option.Option) (*StationToStationResponse, error) {
+	return helpers.Get[StationToStationResponse](ctx, a.requester, helpers.StationToStation, map[string]string{"FromStationCode": string(fromStationCode), "ToStationCode": string(toStationCode)}, opts...)
 }
@@ -0,0 +1,178 @@
+package railstationinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+)
+
+type StationTime struct {
+	Code        ids.StationCode `json:"Code"`
+	StationName string          `json:"StationName"`
+	Monday      DaySchedule     `json:"Monday"`
+	Tuesday     DaySchedule     `json:"Tuesday"`
+	Wednesday   DaySchedule     `json:"Wednesday"`
+	Thursday    DaySchedule     `json:"Thursday"`
+	Friday      DaySchedule     `json:"Friday"`
+	Saturday    DaySchedule     `json:"Saturday"`
+	Sunday      DaySchedule     `json:"Sunday"`
+}
+
+// DaySchedule is the service of a station on a service day. Trains leaving
+// after midnight belong to the service day of the previous date.
+type DaySchedule struct {
+	OpeningTime ServiceTime `json:"OpeningTime"`
+	FirstTrains []Train     `json:"FirstTrains"`
+	LastTrains  []Train     `json:"LastTrains"`
+}
+
+type Train struct {
+	Time               ServiceTime     `json:"Time"`
+	DestinationStation ids.StationCode `json:"DestinationStation"`
+}
+
+// ServiceTime is a time of a service day, as an offset from its midnight.
+// WMATA sends it as "15:04". Times after midnight that belong to the service
+// day, such as late last trains, are 24 hours or more.
+type ServiceTime time.Duration
+
+func (s *ServiceTime) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+	if str == "" {
+		*s = 0
+		return nil
+	}
+	t, err := time.Parse("15:04", str)
+	if err != nil {
+		return fmt.Errorf("time.Parse: %w", err)
+	}
+	*s = ServiceTime(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	return nil
+}
+
+// MarshalJSON writes the time as WMATA sends it, so that on its own a time
+// past midnight loses its day: 24:30 is written as "00:30". DaySchedule
+// restores the day when decoding, as it does for WMATA responses.
+func (s ServiceTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// String returns the time as WMATA sends it, e.g. "00:30" for half past
+// midnight at the end of the service day.
+func (s ServiceTime) String() string {
+	minutes := int(time.Duration(s)/time.Minute) % (24 * 60)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// On returns the time on the service day of day, in America/New_York.
+func (s ServiceTime) On(day time.Time) time.Time {
+	y, m, d := day.In(helpers.Eastern).Date()
+	return time.Date(y, m, d, 0, int(time.Duration(s)/time.Minute), 0, 0, helpers.Eastern)
+}
+
+func (d *DaySchedule) UnmarshalJSON(b []byte) error {
+	type plain DaySchedule
+	if err := json.Unmarshal(b, (*plain)(d)); err != nil {
+		return err
+	}
+	// Trains earlier than the opening time leave after midnight.
+	for _, trains := range [][]Train{d.FirstTrains, d.LastTrains} {
+		for i := range trains {
+			if trains[i].Time < d.OpeningTime {
+				trains[i].Time += ServiceTime(24 * time.Hour)
+			}
+		}
+	}
+	return nil
+}
+
+// ClosingTime returns the departure of the day's last train, or false if the
+// station has no service that day.
+func (d *DaySchedule) ClosingTime() (ServiceTime, bool) {
+	var closing ServiceTime
+	for _, train := range d.LastTrains {
+		closing = max(closing, train.Time)
+	}
+	return closing, len(d.LastTrains) > 0
+}
+
+// Day returns the schedule of a day of the week.
+func (s *StationTime) Day(weekday time.Weekday) *DaySchedule {
+	switch weekday {
+	case time.Monday:
+		return &s.Monday
+	case time.Tuesday:
+		return &s.Tuesday
+	case time.Wednesday:
+		return &s.Wednesday
+	case time.Thursday:
+		return &s.Thursday
+	case time.Friday:
+		return &s.Friday
+	case time.Saturday:
+		return &s.Saturday
+	default:
+		return &s.Sunday
+	}
+}
+
+// IsOpenAt reports whether the station is open at t, from its opening time to
+// the departure of its last train.
+func (s *StationTime) IsOpenAt(t time.Time) bool {
+	// Service of the previous day may still be running after midnight.
+	today := helpers.ServiceDay(t)
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		schedule := s.Day(day.Weekday())
+		closing, ok := schedule.ClosingTime()
+		if ok && !t.Before(schedule.OpeningTime.On(day)) && !t.After(closing.On(day)) {
+			return true
+		}
+	}
+	return false
+}
+
+// FirstTrainAfter returns the departure of the first train of a service day
+// to destination at or after t, looking up to a week ahead. An empty
+// destination matches every train.
+func (s *StationTime) FirstTrainAfter(t time.Time, destination ids.StationCode) (time.Time, bool) {
+	today := helpers.ServiceDay(t)
+	var first time.Time
+	for i := -1; i <= 7; i++ {
+		day := today.AddDate(0, 0, i)
+		for _, train := range s.Day(day.Weekday()).FirstTrains {
+			departure := train.Time.On(day)
+			if matches(train, destination) && !departure.Before(t) && (first.IsZero() || departure.Before(first)) {
+				first = departure
+			}
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// LastTrainBefore returns the departure of the last train of a service day to
+// destination at or before t, looking up to a week back. An empty destination
+// matches every train.
+func (s *StationTime) LastTrainBefore(t time.Time, destination ids.StationCode) (time.Time, bool) {
+	today := helpers.ServiceDay(t)
+	var last time.Time
+	for i := -7; i <= 0; i++ {
+		day := today.AddDate(0, 0, i)
+		for _, train := range s.Day(day.Weekday()).LastTrains {
+			departure := train.Time.On(day)
+			if matches(train, destination) && !departure.After(t) && departure.After(last) {
+				last = departure
+			}
+		}
+	}
+	return last, !last.IsZero()
+}
+
+func matches(train Train, destination ids.StationCode) bool {
+	return destination == "" || train.DestinationStation == destination
+}
@@ -0,0 +1,161 @@
+package railstationinfo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+)
+
+// stationTimeJSON has service until after midnight every day, and until
+// almost 2am on Friday and Saturday nights.
+const stationTimeJSON = `{
+	"Code": "A01",
+	"StationName": "Metro Center",
+	"Monday": {"OpeningTime": "05:00", "FirstTrains": [{"Time": "05:15", "DestinationStation": "A15"}, {"Time": "05:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "23:50", "DestinationStation": "A15"}, {"Time": "00:05", "DestinationStation": "B11"}]},
+	"Tuesday": {"OpeningTime": "05:00", "FirstTrains": [{"Time": "05:15", "DestinationStation": "A15"}, {"Time": "05:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "23:50", "DestinationStation": "A15"}, {"Time": "00:05", "DestinationStation": "B11"}]},
+	"Wednesday": {"OpeningTime": "05:00", "FirstTrains": [{"Time": "05:15", "DestinationStation": "A15"}, {"Time": "05:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "23:50", "DestinationStation": "A15"}, {"Time": "00:05", "DestinationStation": "B11"}]},
+	"Thursday": {"OpeningTime": "05:00", "FirstTrains": [{"Time": "05:15", "DestinationStation": "A15"}, {"Time": "05:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "23:50", "DestinationStation": "A15"}, {"Time": "00:05", "DestinationStation": "B11"}]},
+	"Friday": {"OpeningTime": "05:00", "FirstTrains": [{"Time": "05:15", "DestinationStation": "A15"}, {"Time": "05:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "01:45", "DestinationStation": "A15"}, {"Time": "01:50", "DestinationStation": "B11"}]},
+	"Saturday": {"OpeningTime": "07:00", "FirstTrains": [{"Time": "07:15", "DestinationStation": "A15"}, {"Time": "07:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "01:45", "DestinationStation": "A15"}, {"Time": "01:50", "DestinationStation": "B11"}]},
+	"Sunday": {"OpeningTime": "07:00", "FirstTrains": [{"Time": "07:15", "DestinationStation": "A15"}, {"Time": "07:20", "DestinationStation": "B11"}], "LastTrains": [{"Time": "23:50", "DestinationStation": "A15"}, {"Time": "00:05", "DestinationStation": "B11"}]}
+}`
+
+func loadStationTime(t *testing.T) *StationTime {
+	t.Helper()
+	var s StationTime
+	if err := json.Unmarshal([]byte(stationTimeJSON), &s); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	return &s
+}
+
+// eastern returns a wall-clock time in Eastern. 2024-05-03 is a Friday.
+func eastern(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2024, month, day, hour, minute, 0, 0, helpers.Eastern)
+}
+
+func TestDayScheduleAfterMidnight(t *testing.T) {
+	s := loadStationTime(t)
+	for _, tc := range []struct {
+		weekday time.Weekday
+		closing time.Duration
+	}{
+		{time.Monday, 24*time.Hour + 5*time.Minute},
+		{time.Friday, 25*time.Hour + 50*time.Minute},
+		{time.Saturday, 25*time.Hour + 50*time.Minute},
+		{time.Sunday, 24*time.Hour + 5*time.Minute},
+	} {
+		closing, ok := s.Day(tc.weekday).ClosingTime()
+		if !ok || time.Duration(closing) != tc.closing {
+			t.Errorf("%v: closing = %v, %v; want %v", tc.weekday, time.Duration(closing), ok, tc.closing)
+		}
+	}
+}
+
+func TestIsOpenAt(t *testing.T) {
+	s := loadStationTime(t)
+	for _, tc := range []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday noon", eastern(time.May, 3, 12, 0), true},
+		{"thursday service after midnight", eastern(time.May, 3, 0, 5), true},
+		{"thursday service over", eastern(time.May, 3, 0, 6), false},
+		{"before friday opening", eastern(time.May, 3, 4, 59), false},
+		{"friday opening", eastern(time.May, 3, 5, 0), true},
+		{"friday service after midnight", eastern(time.May, 4, 1, 30), true},
+		{"friday last train", eastern(time.May, 4, 1, 50), true},
+		{"friday service over", eastern(time.May, 4, 1, 51), false},
+		{"before saturday opening", eastern(time.May, 4, 6, 59), false},
+		{"saturday service after midnight", eastern(time.May, 5, 1, 45), true},
+		{"sunday service after midnight", eastern(time.May, 6, 0, 5), true},
+		{"sunday service over", eastern(time.May, 6, 0, 30), false},
+		{"other time zone", time.Date(2024, time.May, 4, 5, 30, 0, 0, time.UTC), true},
+		// Daylight time starts at 2am on Sunday 2024-03-10, during the
+		// Saturday service.
+		{"spring forward", eastern(time.March, 10, 1, 30), true},
+		{"spring forward over", eastern(time.March, 10, 3, 30), false},
+	} {
+		if got := s.IsOpenAt(tc.at); got != tc.want {
+			t.Errorf("%s: IsOpenAt(%v) = %v, want %v", tc.name, tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestFirstTrainAfter(t *testing.T) {
+	s := loadStationTime(t)
+	for _, tc := range []struct {
+		name        string
+		at          time.Time
+		destination ids.StationCode
+		want        time.Time
+	}{
+		{"same day", eastern(time.May, 3, 5, 16), "B11", eastern(time.May, 3, 5, 20)},
+		{"next day", eastern(time.May, 3, 5, 16), "A15", eastern(time.May, 4, 7, 15)},
+		{"during friday late night", eastern(time.May, 4, 1, 0), "", eastern(time.May, 4, 7, 15)},
+		{"saturday evening", eastern(time.May, 4, 23, 0), "B11", eastern(time.May, 5, 7, 20)},
+		{"at departure", eastern(time.May, 6, 5, 15), "A15", eastern(time.May, 6, 5, 15)},
+		// Standard time starts at 2am on Sunday 2024-11-03.
+		{"fall back", eastern(time.November, 2, 23, 0), "", time.Date(2024, time.November, 3, 12, 15, 0, 0, time.UTC)},
+		{"spring forward", eastern(time.March, 9, 23, 0), "", time.Date(2024, time.March, 10, 11, 15, 0, 0, time.UTC)},
+	} {
+		got, ok := s.FirstTrainAfter(tc.at, tc.destination)
+		if !ok || !got.Equal(tc.want) {
+			t.Errorf("%s: FirstTrainAfter(%v, %q) = %v, %v; want %v", tc.name, tc.at, tc.destination, got, ok, tc.want)
+		}
+	}
+	if got, ok := s.FirstTrainAfter(eastern(time.May, 3, 12, 0), "Z99"); ok {
+		t.Errorf("FirstTrainAfter(Z99) = %v, want none", got)
+	}
+}
+
+func TestLastTrainBefore(t *testing.T) {
+	s := loadStationTime(t)
+	for _, tc := range []struct {
+		name        string
+		at          time.Time
+		destination ids.StationCode
+		want        time.Time
+	}{
+		{"friday late night", eastern(time.May, 4, 1, 47), "A15", eastern(time.May, 4, 1, 45)},
+		{"thursday service", eastern(time.May, 4, 1, 47), "B11", eastern(time.May, 3, 0, 5)},
+		{"saturday noon", eastern(time.May, 4, 12, 0), "", eastern(time.May, 4, 1, 50)},
+		{"saturday late night", eastern(time.May, 5, 2, 0), "", eastern(time.May, 5, 1, 50)},
+		{"sunday service", eastern(time.May, 6, 0, 10), "B11", eastern(time.May, 6, 0, 5)},
+		{"at departure", eastern(time.May, 5, 23, 50), "A15", eastern(time.May, 5, 23, 50)},
+	} {
+		got, ok := s.LastTrainBefore(tc.at, tc.destination)
+		if !ok || !got.Equal(tc.want) {
+			t.Errorf("%s: LastTrainBefore(%v, %q) = %v, %v; want %v", tc.name, tc.at, tc.destination, got, ok, tc.want)
+		}
+	}
+}
+
+func TestServiceTimeJSON(t *testing.T) {
+	late := ServiceTime(24*time.Hour + 30*time.Minute)
+	b, err := json.Marshal(late)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"00:30"` {
+		t.Errorf("Marshal(24:30) = %s, want \"00:30\"", b)
+	}
+
+	// A schedule keeps times past midnight through a round trip.
+	s := loadStationTime(t)
+	b, err = json.Marshal(s.Friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var friday DaySchedule
+	if err := json.Unmarshal(b, &friday); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := friday.LastTrains[1].Time, s.Friday.LastTrains[1].Time; got != want {
+		t.Errorf("last train after round trip = %v, want %v", time.Duration(got), time.Duration(want))
+	}
+}
@@ -0,0 +1,39 @@
+// Package stations is the registry of WMATA rail station platforms. Stations
+// served by several platforms, such as Metro Center, have a code per platform
+// and are named by line, e.g. MetroCenterRed and MetroCenterBlue.
+package stations
+
+import "github.com/thompsonja/wmata-go/pkg/ids"
+
+//go:generate go run ../../cmd/wmata-registry -kind stations -lines ../lines/lines.json -stations stations.json -o codes.go
+
+type station struct {
+	name     string
+	lines    []ids.LineCode
+	together ids.StationCode
+}
+
+// Name returns the name of a station, e.g. "Metro Center". It can be used as
+// a railpredictions.StationResolver.
+func Name(code ids.StationCode) (string, bool) {
+	s, ok := stations[code]
+	return s.name, ok
+}
+
+// Lines returns the lines serving a station platform.
+func Lines(code ids.StationCode) []ids.LineCode {
+	return stations[code].lines
+}
+
+// Together returns the other platform of a station served by several
+// platforms, or false.
+func Together(code ids.StationCode) (ids.StationCode, bool) {
+	s := stations[code]
+	return s.together, s.together != ""
+}
+
+// Known reports whether code is a station in the registry.
+func Known(code ids.StationCode) bool {
+	_, ok := stations[code]
+	return ok
+}
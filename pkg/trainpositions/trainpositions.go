@@ -2,38 +2,42 @@ package trainpositions
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"time"
 
 	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/ids"
+	"github.com/thompsonja/wmata-go/pkg/option"
 )
 
 type TrainPosition struct {
-	TrainId                string  `json:"TrainId"`
-	TrainNumber            string  `json:"TrainNumber"`
-	CarCount               int     `json:"CarCount"`
-	DirectionNum           int     `json:"DirectionNum"`
-	CircuitId              int     `json:"CircuitId"`
-	DestinationStationCode *string `json:"DestinationStationCode"`
-	LineCode               *string `json:"LineCode"`
-	SecondsAtLocation      int     `json:"SecondsAtLocation"`
-	ServiceType            string  `json:"ServiceType"`
+	TrainId                string           `json:"TrainId"`
+	TrainNumber            string           `json:"TrainNumber"`
+	CarCount               int              `json:"CarCount"`
+	DirectionNum           int              `json:"DirectionNum"`
+	CircuitId              int              `json:"CircuitId"`
+	DestinationStationCode *ids.StationCode `json:"DestinationStationCode"`
+	LineCode               *ids.LineCode    `json:"LineCode"`
+	SecondsAtLocation      int              `json:"SecondsAtLocation"`
+	ServiceType            string           `json:"ServiceType"`
 }
 
 type TrainPositionResponse struct {
 	TrainPositions []TrainPosition `json:"TrainPositions"`
+	// Stale and Age are those of option.ResponseMeta.
+	Stale bool          `json:"-"`
+	Age   time.Duration `json:"-"`
 }
 
 type StandardRoute struct {
-	LineCode      string         `json:"LineCode"`
+	LineCode      ids.LineCode   `json:"LineCode"`
 	TrackNum      int            `json:"TrackNum"`
 	TrackCircuits []TrackCircuit `json:"TrackCircuits"`
 }
 
 type TrackCircuit struct {
-	SeqNum      int     `json:"SeqNum"`
-	CircuitId   int     `json:"CircuitId"`
-	StationCode *string `json:"StationCode"`
+	SeqNum      int              `json:"SeqNum"`
+	CircuitId   int              `json:"CircuitId"`
+	StationCode *ids.StationCode `json:"StationCode"`
 }
 
 type StandardRoutesResponse struct {
@@ -59,62 +63,48 @@ type API struct {
 	requester *helpers.HttpRequester
 }
 
-func New(apiKey string) *API {
+func New(apiKey string, opts ...option.Option) *API {
 	return &API{
-		requester: helpers.New(apiKey),
+		requester: helpers.New(apiKey, opts...),
 	}
 }
 
-func (a *API) GetTrainPositions(ctx context.Context) (*TrainPositionResponse, error) {
-	url, err := helpers.GenerateUrl("TrainPositions/TrainPositions?contentType=json", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
+func NewWithRequester(requester *helpers.HttpRequester) *API {
+	return &API{
+		requester: requester,
 	}
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response TrainPositionResponse
-	err = json.Unmarshal(responseBody, &response)
+func (a *API) GetTrainPositions(ctx context.Context, opts ...option.Option) (*TrainPositionResponse, error) {
+	response, res, err := helpers.GetResult[TrainPositionResponse](ctx, a.requester, helpers.TrainPositions, nil, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
+		return nil, err
 	}
-	return &response, nil
+	response.Stale = res.Stale
+	response.Age = res.Age
+	return response, nil
 }
 
-func (a *API) GetStandardRoutes(ctx context.Context) (*StandardRoutesResponse, error) {
-	url, err := helpers.GenerateUrl("TrainPositions/StandardRoutes?contentType=json", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+func (a *API) GetStandardRoutes(ctx context.Context, opts ...option.Option) (*StandardRoutesResponse, error) {
+	return helpers.Get[StandardRoutesResponse](ctx, a.requester, helpers.StandardRoutes, nil, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response StandardRoutesResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+func (a *API) GetTrackCircuits(ctx context.Context, opts ...option.Option) (*TrackCircuitsResponse, error) {
+	return helpers.Get[TrackCircuitsResponse](ctx, a.requester, helpers.TrackCircuits, nil, opts...)
 }
 
-func (a *API) GetTrackCircuits(ctx context.Context) (*TrackCircuitsResponse, error) {
-	url, err := helpers.GenerateUrl("TrainPositions/TrackCircuits?contentType=json", nil)
-	if err != nil {
-		return nil, fmt.Errorf("helpers.GenerateUrl: %v", err)
-	}
+// ForEachTrackCircuit calls fn with every track circuit as it is decoded,
+// without holding the whole response in memory.
+func (a *API) ForEachTrackCircuit(ctx context.Context, fn func(TrackCircuitData) error, opts ...option.Option) error {
+	return helpers.ForEach(ctx, a.requester, helpers.TrackCircuits, nil, func(_ string, circuit TrackCircuitData) error {
+		return fn(circuit)
+	}, []string{"TrackCircuits"}, opts...)
+}
 
-	responseBody, err := a.requester.SendHttpRequest(ctx, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("a.requester.SendHttpRequest: %v", err)
-	}
-	var response TrackCircuitsResponse
-	err = json.Unmarshal(responseBody, &response)
-	if err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %v", err)
-	}
-	return &response, nil
+// ForEachStandardRoute calls fn with every standard route as it is decoded,
+// without holding the whole response in memory.
+func (a *API) ForEachStandardRoute(ctx context.Context, fn func(StandardRoute) error, opts ...option.Option) error {
+	return helpers.ForEach(ctx, a.requester, helpers.StandardRoutes, nil, func(_ string, route StandardRoute) error {
+		return fn(route)
+	}, []string{"StandardRoutes"}, opts...)
 }
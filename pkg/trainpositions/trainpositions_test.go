@@ -0,0 +1,90 @@
+package trainpositions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/option"
+)
+
+// trackCircuitsServer serves a TrackCircuits response of about the size WMATA
+// sends.
+func trackCircuitsServer(b *testing.B) *httptest.Server {
+	b.Helper()
+	var response TrackCircuitsResponse
+	for i := 0; i < 3500; i++ {
+		response.TrackCircuits = append(response.TrackCircuits, TrackCircuitData{
+			Track:     i % 2,
+			CircuitId: i,
+			Neighbors: []NeighborData{
+				{NeighborType: "Left", CircuitIds: []int{i - 1}},
+				{NeighborType: "Right", CircuitIds: []int{i + 1}},
+			},
+		})
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		b.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	b.Cleanup(srv.Close)
+	return srv
+}
+
+func newRequester(srv *httptest.Server) *helpers.HttpRequester {
+	return helpers.New("key", option.WithBaseURL(srv.URL+"/"), option.WithRateLimit(option.RateLimit{}))
+}
+
+func BenchmarkGetTrackCircuits(b *testing.B) {
+	srv := trackCircuitsServer(b)
+	ctx := context.Background()
+
+	b.Run("buffered", func(b *testing.B) {
+		r := newRequester(srv)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			res, err := helpers.Send(ctx, r, helpers.TrackCircuits, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var response TrackCircuitsResponse
+			if err := json.Unmarshal(res.Body, &response); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("streamed", func(b *testing.B) {
+		a := NewWithRequester(newRequester(srv))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := a.GetTrackCircuits(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkForEachTrackCircuit(b *testing.B) {
+	a := NewWithRequester(newRequester(trackCircuitsServer(b)))
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		err := a.ForEachTrackCircuit(ctx, func(TrackCircuitData) error {
+			n++
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != 3500 {
+			b.Fatalf("got %d track circuits, want 3500", n)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package wmata
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/thompsonja/wmata-go/pkg/option"
+)
+
+// Response is a decoded value along with exactly what WMATA sent.
+type Response[T any] struct {
+	Value      *T
+	Raw        json.RawMessage
+	Header     http.Header
+	StatusCode int
+	// URL is the request URL. The API key is sent as a header, so it never
+	// appears in it.
+	URL       string
+	FetchedAt time.Time
+	Latency   time.Duration
+	CacheHit  bool
+	Stale     bool
+	Age       time.Duration
+}
+
+// WithResponse calls an API method, passing it the options it receives, and
+// returns the decoded value with the response metadata:
+//
+//	resp, err := wmata.WithResponse(func(opts ...option.Option) (*railpredictions.RailPredictions, error) {
+//		return client.RailPredictions.GetRailPredictions(ctx, ids.AllStations, opts...)
+//	})
+func WithResponse[T any](call func(opts ...option.Option) (*T, error)) (*Response[T], error) {
+	var meta option.ResponseMeta
+	value, err := call(option.WithResponseMeta(&meta))
+	if err != nil {
+		return nil, err
+	}
+	return &Response[T]{
+		Value:      value,
+		Raw:        meta.Body,
+		Header:     meta.Header,
+		StatusCode: meta.StatusCode,
+		URL:        meta.URL,
+		FetchedAt:  meta.FetchedAt,
+		Latency:    meta.Latency,
+		CacheHit:   meta.CacheHit,
+		Stale:      meta.Stale,
+		Age:        meta.Age,
+	}, nil
+}
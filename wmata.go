@@ -0,0 +1,47 @@
+// Package wmata is the entry point of the WMATA SDK. A Client owns a single
+// configured requester that is shared by every service.
+package wmata
+
+import (
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/businfo"
+	"github.com/thompsonja/wmata-go/pkg/buspredictions"
+	"github.com/thompsonja/wmata-go/pkg/incidents"
+	"github.com/thompsonja/wmata-go/pkg/misc"
+	"github.com/thompsonja/wmata-go/pkg/option"
+	"github.com/thompsonja/wmata-go/pkg/railpredictions"
+	"github.com/thompsonja/wmata-go/pkg/railstationinfo"
+	"github.com/thompsonja/wmata-go/pkg/trainpositions"
+)
+
+type Client struct {
+	Rail            *railstationinfo.API
+	RailPredictions *railpredictions.API
+	Bus             *businfo.API
+	BusPredictions  *buspredictions.API
+	Incidents       *incidents.API
+	TrainPositions  *trainpositions.API
+	Misc            *misc.API
+
+	requester *helpers.HttpRequester
+}
+
+func New(apiKey string, opts ...option.Option) *Client {
+	requester := helpers.New(apiKey, opts...)
+	return &Client{
+		Rail:            railstationinfo.NewWithRequester(requester),
+		RailPredictions: railpredictions.NewWithRequester(requester),
+		Bus:             businfo.NewWithRequester(requester),
+		BusPredictions:  buspredictions.NewWithRequester(requester),
+		Incidents:       incidents.NewWithRequester(requester),
+		TrainPositions:  trainpositions.NewWithRequester(requester),
+		Misc:            misc.NewWithRequester(requester),
+		requester:       requester,
+	}
+}
+
+// RemainingDailyBudget returns the number of calls left in today's
+// client-side budget, or -1 if there is no daily limit.
+func (c *Client) RemainingDailyBudget() int {
+	return c.requester.RemainingDailyBudget()
+}
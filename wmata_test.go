@@ -0,0 +1,364 @@
+package wmata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thompsonja/wmata-go/internal/helpers"
+	"github.com/thompsonja/wmata-go/pkg/businfo"
+	"github.com/thompsonja/wmata-go/pkg/option"
+	"github.com/thompsonja/wmata-go/pkg/railstationinfo"
+	"github.com/thompsonja/wmata-go/pkg/trainpositions"
+)
+
+// TestEndpointParameters checks that every method sends exactly the path and
+// query parameters documented by WMATA.
+func TestEndpointParameters(t *testing.T) {
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, helpers.Eastern)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		call  func(c *Client) error
+		path  string
+		query url.Values
+	}{
+		{
+			name: "GetBusPositions",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetBusPositions(ctx, businfo.BusPositionsRequest{RouteID: "70", Lat: 38.9, Lon: -77.03, Radius: 500})
+				return err
+			},
+			path:  "/Bus.svc/json/jBusPositions",
+			query: url.Values{"RouteID": {"70"}, "Lat": {"38.9"}, "Lon": {"-77.03"}, "Radius": {"500"}},
+		},
+		{
+			name: "GetBusPositions unset",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetBusPositions(ctx, businfo.BusPositionsRequest{})
+				return err
+			},
+			path:  "/Bus.svc/json/jBusPositions",
+			query: url.Values{},
+		},
+		{
+			name: "GetPathDetails",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetPathDetails(ctx, businfo.PathDetailsRequest{RouteID: "70", Date: date})
+				return err
+			},
+			path:  "/Bus.svc/json/jRouteDetails",
+			query: url.Values{"RouteID": {"70"}, "Date": {"2024-05-01"}},
+		},
+		{
+			name: "GetPathDetails unset date",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetPathDetails(ctx, businfo.PathDetailsRequest{RouteID: "70"})
+				return err
+			},
+			path:  "/Bus.svc/json/jRouteDetails",
+			query: url.Values{"RouteID": {"70"}},
+		},
+		{
+			// 1am UTC is still the previous day in Washington.
+			name: "GetPathDetails UTC date",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetPathDetails(ctx, businfo.PathDetailsRequest{RouteID: "70", Date: time.Date(2024, 5, 2, 1, 0, 0, 0, time.UTC)})
+				return err
+			},
+			path:  "/Bus.svc/json/jRouteDetails",
+			query: url.Values{"RouteID": {"70"}, "Date": {"2024-05-01"}},
+		},
+		{
+			name: "GetRoutes",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetRoutes(ctx)
+				return err
+			},
+			path:  "/Bus.svc/json/jRoutes",
+			query: url.Values{},
+		},
+		{
+			name: "GetSchedule",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetSchedule(ctx, businfo.ScheduleRequest{RouteID: "70", Date: date, IncludingVariations: true})
+				return err
+			},
+			path:  "/Bus.svc/json/jRouteSchedule",
+			query: url.Values{"RouteID": {"70"}, "Date": {"2024-05-01"}, "IncludingVariations": {"true"}},
+		},
+		{
+			name: "ForEachScheduledTrip",
+			call: func(c *Client) error {
+				return c.Bus.ForEachScheduledTrip(ctx, businfo.ScheduleRequest{RouteID: "70"}, func(int, businfo.Trip) error { return nil })
+			},
+			path:  "/Bus.svc/json/jRouteSchedule",
+			query: url.Values{"RouteID": {"70"}},
+		},
+		{
+			name: "GetScheduleAtStop",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetScheduleAtStop(ctx, businfo.ScheduleAtStopRequest{StopID: "1001195", Date: date, IncludingVariations: true})
+				return err
+			},
+			path:  "/Bus.svc/json/jStopSchedule",
+			query: url.Values{"StopID": {"1001195"}, "Date": {"2024-05-01"}, "IncludingVariations": {"true"}},
+		},
+		{
+			name: "GetScheduleAtStop unset",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetScheduleAtStop(ctx, businfo.ScheduleAtStopRequest{StopID: "1001195"})
+				return err
+			},
+			path:  "/Bus.svc/json/jStopSchedule",
+			query: url.Values{"StopID": {"1001195"}},
+		},
+		{
+			name: "GetStops",
+			call: func(c *Client) error {
+				_, err := c.Bus.GetStops(ctx, businfo.StopsRequest{Lat: 38.9, Lon: -77.03, Radius: 500})
+				return err
+			},
+			path:  "/Bus.svc/json/jStops",
+			query: url.Values{"Lat": {"38.9"}, "Lon": {"-77.03"}, "Radius": {"500"}},
+		},
+		{
+			name: "GetBusPredictions",
+			call: func(c *Client) error {
+				_, err := c.BusPredictions.GetBusPredictions(ctx, "1001195")
+				return err
+			},
+			path:  "/NextBusService.svc/json/jPredictions",
+			query: url.Values{"StopID": {"1001195"}},
+		},
+		{
+			name: "GetBusIncidents",
+			call: func(c *Client) error {
+				_, err := c.Incidents.GetBusIncidents(ctx, "70")
+				return err
+			},
+			path:  "/Incidents.svc/json/BusIncidents",
+			query: url.Values{"Route": {"70"}},
+		},
+		{
+			name: "GetBusIncidents unset",
+			call: func(c *Client) error {
+				_, err := c.Incidents.GetBusIncidents(ctx, "")
+				return err
+			},
+			path:  "/Incidents.svc/json/BusIncidents",
+			query: url.Values{},
+		},
+		{
+			name: "GetElevatorIncidents",
+			call: func(c *Client) error {
+				_, err := c.Incidents.GetElevatorIncidents(ctx, "A01")
+				return err
+			},
+			path:  "/Incidents.svc/json/ElevatorIncidents",
+			query: url.Values{"StationCode": {"A01"}},
+		},
+		{
+			name: "GetRailIncidents",
+			call: func(c *Client) error {
+				_, err := c.Incidents.GetRailIncidents(ctx)
+				return err
+			},
+			path:  "/Incidents.svc/json/Incidents",
+			query: url.Values{},
+		},
+		{
+			name: "Validate",
+			call: func(c *Client) error {
+				return c.Misc.Validate(ctx)
+			},
+			path:  "/Misc/Validate",
+			query: url.Values{},
+		},
+		{
+			name: "GetRailPredictions",
+			call: func(c *Client) error {
+				_, err := c.RailPredictions.GetRailPredictions(ctx, "A01,C01")
+				return err
+			},
+			path:  "/StationPrediction.svc/json/GetPrediction/A01,C01",
+			query: url.Values{},
+		},
+		{
+			name: "GetLines",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetLines(ctx)
+				return err
+			},
+			path:  "/Rail.svc/json/jLines",
+			query: url.Values{},
+		},
+		{
+			name: "GetParkingInfo",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetParkingInfo(ctx, "K08")
+				return err
+			},
+			path:  "/Rail.svc/json/jStationParking",
+			query: url.Values{"StationCode": {"K08"}},
+		},
+		{
+			name: "GetPathBetweenStations",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetPathBetweenStations(ctx, "N06", "G05")
+				return err
+			},
+			path:  "/Rail.svc/json/jPath",
+			query: url.Values{"FromStationCode": {"N06"}, "ToStationCode": {"G05"}},
+		},
+		{
+			name: "GetStationEntrances",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStationEntrances(ctx, railstationinfo.StationEntrancesRequest{Lat: 38.9, Lon: -77.03, Radius: 500})
+				return err
+			},
+			path:  "/Rail.svc/json/jStationEntrances",
+			query: url.Values{"Lat": {"38.9"}, "Lon": {"-77.03"}, "Radius": {"500"}},
+		},
+		{
+			name: "GetStationInfo",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStationInfo(ctx, "A01")
+				return err
+			},
+			path:  "/Rail.svc/json/jStationInfo",
+			query: url.Values{"StationCode": {"A01"}},
+		},
+		{
+			name: "GetStations",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStations(ctx, "RD")
+				return err
+			},
+			path:  "/Rail.svc/json/jStations",
+			query: url.Values{"LineCode": {"RD"}},
+		},
+		{
+			name: "GetStations unset",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStations(ctx, "")
+				return err
+			},
+			path:  "/Rail.svc/json/jStations",
+			query: url.Values{},
+		},
+		{
+			name: "GetStationTimings",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStationTimings(ctx, "A01")
+				return err
+			},
+			path:  "/Rail.svc/json/jStationTimes",
+			query: url.Values{"StationCode": {"A01"}},
+		},
+		{
+			name: "GetStationToStationInfo",
+			call: func(c *Client) error {
+				_, err := c.Rail.GetStationToStationInfo(ctx, "A01", "K08")
+				return err
+			},
+			path:  "/Rail.svc/json/jSrcStationToDstStationInfo",
+			query: url.Values{"FromStationCode": {"A01"}, "ToStationCode": {"K08"}},
+		},
+		{
+			name: "GetTrainPositions",
+			call: func(c *Client) error {
+				_, err := c.TrainPositions.GetTrainPositions(ctx)
+				return err
+			},
+			path:  "/TrainPositions/TrainPositions",
+			query: url.Values{"contentType": {"json"}},
+		},
+		{
+			name: "GetStandardRoutes",
+			call: func(c *Client) error {
+				_, err := c.TrainPositions.GetStandardRoutes(ctx)
+				return err
+			},
+			path:  "/TrainPositions/StandardRoutes",
+			query: url.Values{"contentType": {"json"}},
+		},
+		{
+			name: "ForEachStandardRoute",
+			call: func(c *Client) error {
+				return c.TrainPositions.ForEachStandardRoute(ctx, func(trainpositions.StandardRoute) error { return nil })
+			},
+			path:  "/TrainPositions/StandardRoutes",
+			query: url.Values{"contentType": {"json"}},
+		},
+		{
+			name: "GetTrackCircuits",
+			call: func(c *Client) error {
+				_, err := c.TrainPositions.GetTrackCircuits(ctx)
+				return err
+			},
+			path:  "/TrainPositions/TrackCircuits",
+			query: url.Values{"contentType": {"json"}},
+		},
+		{
+			name: "ForEachTrackCircuit",
+			call: func(c *Client) error {
+				return c.TrainPositions.ForEachTrackCircuit(ctx, func(trainpositions.TrackCircuitData) error { return nil })
+			},
+			path:  "/TrainPositions/TrackCircuits",
+			query: url.Values{"contentType": {"json"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				w.Write([]byte("{}"))
+			}))
+			defer srv.Close()
+
+			c := New("key", option.WithBaseURL(srv.URL+"/"), option.WithRetryPolicy(option.NoRetry))
+			if err := tt.call(c); err != nil {
+				t.Fatalf("call: %v", err)
+			}
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			r := requests[0]
+			if r.URL.EscapedPath() != tt.path {
+				t.Errorf("path = %q, want %q", r.URL.EscapedPath(), tt.path)
+			}
+			if got := r.URL.Query(); !reflect.DeepEqual(got, tt.query) {
+				t.Errorf("query = %v, want %v", got, tt.query)
+			}
+		})
+	}
+}
+
+func TestPerCallBaseURL(t *testing.T) {
+	hits := map[string]int{}
+	newServer := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[name]++
+			w.Write([]byte("{}"))
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	client, call := newServer("client"), newServer("call")
+
+	c := New("key", option.WithBaseURL(client.URL+"/"))
+	if _, err := c.Rail.GetLines(context.Background(), option.WithBaseURL(call.URL+"/")); err != nil {
+		t.Fatalf("GetLines: %v", err)
+	}
+	if hits["client"] != 0 || hits["call"] != 1 {
+		t.Errorf("hits = %v, want only the per-call base URL", hits)
+	}
+}